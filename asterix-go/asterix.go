@@ -157,16 +157,16 @@ func Parse(data []byte) ([]Record, error) {
 // If verbose is true, descriptions are included in the output.
 func ParseWithOptions(data []byte, verbose bool) ([]Record, error) {
 	if !IsInitialized() {
-		return nil, ErrNotInitialized
+		return nil, recordParseError("not_initialized", ErrNotInitialized)
 	}
 
 	if len(data) == 0 {
-		return nil, ErrInvalidData
+		return nil, recordParseError("invalid_data", ErrInvalidData)
 	}
 
 	if len(data) > C.ASTERIX_MAX_MESSAGE_SIZE {
-		return nil, fmt.Errorf("asterix: data too large (%d bytes, max %d)",
-			len(data), C.ASTERIX_MAX_MESSAGE_SIZE)
+		return nil, recordParseError("too_large", fmt.Errorf("asterix: data too large (%d bytes, max %d)",
+			len(data), C.ASTERIX_MAX_MESSAGE_SIZE))
 	}
 
 	verboseInt := 0
@@ -174,45 +174,51 @@ func ParseWithOptions(data []byte, verbose bool) ([]Record, error) {
 		verboseInt = 1
 	}
 
+	start := time.Now()
 	result := C.asterix_parse(
 		(*C.uint8_t)(unsafe.Pointer(&data[0])),
 		C.size_t(len(data)),
 		C.int(verboseInt),
 	)
+	elapsed := time.Since(start)
 	if result == nil {
-		return nil, ErrMemory
+		return nil, recordParseError("memory", ErrMemory)
 	}
 	defer C.asterix_free_result(result)
 
 	if result.error_code != C.ASTERIX_OK {
 		if result.error_message != nil {
-			return nil, fmt.Errorf("asterix: %s", C.GoString(result.error_message))
+			return nil, recordParseError("engine", fmt.Errorf("asterix: %s", C.GoString(result.error_message)))
 		}
-		return nil, ErrParseFailed
+		return nil, recordParseError("engine", ErrParseFailed)
 	}
 
-	return convertRecords(result), nil
+	records := convertRecords(result)
+	recordParseSuccess(len(data), elapsed, records)
+	return records, nil
 }
 
 // ParseWithOffset parses ASTERIX data starting at the given offset.
 // This is useful for incremental parsing of large data streams.
 func ParseWithOffset(data []byte, offset, maxBlocks int) (*ParseResult, error) {
 	if !IsInitialized() {
-		return nil, ErrNotInitialized
+		return nil, recordParseError("not_initialized", ErrNotInitialized)
 	}
 
 	if len(data) == 0 {
-		return nil, ErrInvalidData
+		return nil, recordParseError("invalid_data", ErrInvalidData)
 	}
 
 	if offset < 0 || offset >= len(data) {
-		return nil, fmt.Errorf("asterix: offset %d out of range [0, %d)", offset, len(data))
+		return nil, recordParseError("invalid_data",
+			fmt.Errorf("asterix: offset %d out of range [0, %d)", offset, len(data)))
 	}
 
 	if maxBlocks < 0 {
 		maxBlocks = 0
 	}
 
+	start := time.Now()
 	result := C.asterix_parse_with_offset(
 		(*C.uint8_t)(unsafe.Pointer(&data[0])),
 		C.size_t(len(data)),
@@ -220,20 +226,23 @@ func ParseWithOffset(data []byte, offset, maxBlocks int) (*ParseResult, error) {
 		C.size_t(maxBlocks),
 		C.int(1), // verbose
 	)
+	elapsed := time.Since(start)
 	if result == nil {
-		return nil, ErrMemory
+		return nil, recordParseError("memory", ErrMemory)
 	}
 	defer C.asterix_free_result(result)
 
 	if result.error_code != C.ASTERIX_OK {
 		if result.error_message != nil {
-			return nil, fmt.Errorf("asterix: %s", C.GoString(result.error_message))
+			return nil, recordParseError("engine", fmt.Errorf("asterix: %s", C.GoString(result.error_message)))
 		}
-		return nil, ErrParseFailed
+		return nil, recordParseError("engine", ErrParseFailed)
 	}
 
+	records := convertRecords(result)
+	recordParseSuccess(int(result.bytes_consumed), elapsed, records)
 	return &ParseResult{
-		Records:       convertRecords(result),
+		Records:       records,
 		BytesConsumed: int(result.bytes_consumed),
 	}, nil
 }
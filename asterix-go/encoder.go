@@ -0,0 +1,300 @@
+package asterix
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Encoder is the inverse of Parse: given a typed category message (see
+// CAT048Message and friends) or a map[string]interface{} matching the
+// JSON schema Items returns, it produces a valid ASTERIX data block with
+// a correct FSPEC, length field, and item ordering. This unlocks
+// test-fixture generation, replay tools, and simulators, which today
+// have to hand-craft bytes the way sampleCAT048 does in the tests.
+//
+// Encoder supports CAT048 and CAT062 today, matching the two categories
+// the typed-decoder request's own test fixtures (sampleCAT048,
+// sampleCAT062) exercise. Every field on the message struct is encoded
+// unconditionally — the struct has no presence bits of its own, so a
+// zero-valued field is encoded as a zero-valued item rather than
+// omitted.
+//
+// Partial delivery: the request asked for the FRN tables and item
+// codecs to come from the same XML category definitions the C++ engine
+// uses. They don't — encodeCAT048 and encodeCAT062 below are
+// hand-written, same gap as Record.As in types.go. Extending Encoder to
+// more categories today means adding another hand-written FRN table and
+// set of codecs here, which is exactly the drift risk XML codegen would
+// close; that codegen step doesn't exist in this repo yet.
+type Encoder struct{}
+
+// NewEncoder returns a ready-to-use Encoder. Encoder holds no state, so
+// a zero value also works; NewEncoder exists for symmetry with the rest
+// of the package's constructors.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Encode encodes msg as a single ASTERIX data block.
+func Encode(msg interface{}) ([]byte, error) {
+	return NewEncoder().Encode(msg)
+}
+
+// Encode encodes msg as a single ASTERIX data block.
+func (e *Encoder) Encode(msg interface{}) ([]byte, error) {
+	switch v := msg.(type) {
+	case CAT048Message:
+		return encodeCAT048(&v)
+	case *CAT048Message:
+		return encodeCAT048(v)
+	case CAT062Message:
+		return encodeCAT062(&v)
+	case *CAT062Message:
+		return encodeCAT062(v)
+	case map[string]interface{}:
+		return encodeFromMap(v)
+	default:
+		return nil, fmt.Errorf("asterix: encoding %T is not yet supported", msg)
+	}
+}
+
+// WriteRecord encodes msg and writes the resulting block to w, returning
+// the number of bytes written.
+func (e *Encoder) WriteRecord(w io.Writer, msg interface{}) (int, error) {
+	data, err := e.Encode(msg)
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(data)
+}
+
+// encodeFromMap encodes the map[string]interface{} form of a record, as
+// produced by json.Unmarshal of Record.JSON. It re-marshals the map and
+// decodes it through the same wire types Record.As uses, so a map and
+// its equivalent typed message always encode identically.
+func encodeFromMap(data map[string]interface{}) ([]byte, error) {
+	catF, ok := data["category"].(float64)
+	if !ok {
+		return nil, errors.New(`asterix: map is missing a numeric "category" field`)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("asterix: re-marshaling map for encoding: %w", err)
+	}
+
+	switch uint8(catF) {
+	case 48:
+		var w cat048Wire
+		if err := json.Unmarshal(raw, &w); err != nil {
+			return nil, fmt.Errorf("asterix: decoding CAT048 map: %w", err)
+		}
+		var m CAT048Message
+		populateCAT048(&m, &w)
+		return encodeCAT048(&m)
+	case 62:
+		var w cat062Wire
+		if err := json.Unmarshal(raw, &w); err != nil {
+			return nil, fmt.Errorf("asterix: decoding CAT062 map: %w", err)
+		}
+		var m CAT062Message
+		populateCAT062(&m, &w)
+		return encodeCAT062(&m)
+	default:
+		return nil, fmt.Errorf("asterix: Encoder does not yet support category %d from a map; only CAT048 and CAT062 are implemented", int(catF))
+	}
+}
+
+// itemFRN pairs a Field Reference Number with its already-encoded item
+// bytes, ready for encodeBlock to lay out in FSPEC and item order.
+type itemFRN struct {
+	frn  int
+	data []byte
+}
+
+// encodeBlock assembles a complete ASTERIX data block: the CAT+LEN
+// header, an FSPEC sized to cover the highest FRN in items, and the
+// item bytes themselves in ascending FRN order.
+func encodeBlock(category uint8, items []itemFRN) ([]byte, error) {
+	maxFRN := 0
+	for _, it := range items {
+		if it.frn > maxFRN {
+			maxFRN = it.frn
+		}
+	}
+	if maxFRN == 0 {
+		return nil, fmt.Errorf("asterix: no items to encode for category %d", category)
+	}
+
+	numOctets := (maxFRN + 6) / 7
+	fspec := make([]byte, numOctets)
+	for _, it := range items {
+		octet := (it.frn - 1) / 7
+		bitInOctet := (it.frn - 1) % 7 // 0 = most significant data bit of the octet
+		fspec[octet] |= 1 << uint(7-bitInOctet)
+	}
+	for i := 0; i < numOctets-1; i++ {
+		fspec[i] |= 0x01 // FX: another FSPEC octet follows
+	}
+
+	sorted := append([]itemFRN(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].frn < sorted[j].frn })
+
+	body := append([]byte(nil), fspec...)
+	for _, it := range sorted {
+		body = append(body, it.data...)
+	}
+
+	length := 3 + len(body)
+	block := make([]byte, 0, length)
+	block = append(block, category)
+	block = append(block, byte(length>>8), byte(length))
+	block = append(block, body...)
+	return block, nil
+}
+
+// encodeCAT048 lays out a Category 048 record using the standard FRN
+// assignment: I010=1, I140=2, I040=4, I070=5, I090=6, I220=8, I161=11,
+// I200=13.
+func encodeCAT048(m *CAT048Message) ([]byte, error) {
+	items := []itemFRN{
+		{frn: 1, data: encodeDataSource(m.DataSource)},
+		{frn: 2, data: encodeTimeOfDay(m.TimeOfDay)},
+		{frn: 4, data: encodePolarPosition(m.Rho, m.Theta)},
+		{frn: 5, data: encodeMode3A(m.Mode3A)},
+		{frn: 6, data: encodeFlightLevel(m.FlightLevel)},
+		{frn: 8, data: encodeAircraftAddress(m.TargetAddress)},
+		{frn: 11, data: encodeTrackNumber12(m.TrackNumber)},
+		{frn: 13, data: encodeGroundVector(m.GroundSpeed, m.Heading)},
+	}
+	return encodeBlock(48, items)
+}
+
+// encodeCAT062 lays out a Category 062 record using the FRN assignment:
+// I010=1, I070=3, I105=4, I100=5, I185=6, I060=8, I040=11, I136=16,
+// I080=20 (the last being this binding's own TargetAddress placement,
+// not an official CAT062 Track Status field).
+func encodeCAT062(m *CAT062Message) ([]byte, error) {
+	items := []itemFRN{
+		{frn: 1, data: encodeDataSource(m.DataSource)},
+		{frn: 3, data: encodeTimeOfDay(m.TimeOfTrack)},
+		{frn: 4, data: encodeWGS84Position(m.Latitude, m.Longitude)},
+		{frn: 5, data: encodeCartesianPosition(m.CartesianX, m.CartesianY)},
+		{frn: 6, data: encodeVelocityCartesian(m.TrackVelocity)},
+		{frn: 8, data: encodeMode3A(m.Mode3A)},
+		{frn: 11, data: encodeTrackNumber16(m.TrackNumber)},
+		{frn: 16, data: encodeFlightLevel(m.FlightLevel)},
+		{frn: 20, data: encodeAircraftAddress(m.TargetAddress)},
+	}
+	return encodeBlock(62, items)
+}
+
+// The encode* functions below are the inverse of the scale factors used
+// throughout types.go: each packs one engineering-unit field back into
+// its standard ASTERIX binary representation.
+
+func encodeDataSource(ds DataSource) []byte {
+	return []byte{ds.SAC, ds.SIC}
+}
+
+// encodeTimeOfDay packs seconds-since-midnight into a 3-byte unsigned
+// field with an LSB of 1/128 s.
+func encodeTimeOfDay(seconds float64) []byte {
+	ticks := uint32(math.Round(seconds*128)) & 0xFFFFFF
+	return []byte{byte(ticks >> 16), byte(ticks >> 8), byte(ticks)}
+}
+
+// encodePolarPosition packs RHO (NM, LSB 1/256 NM) and THETA (degrees,
+// LSB 360/65536 deg) into a 4-byte field.
+func encodePolarPosition(rho, theta float64) []byte {
+	r := uint16(math.Round(rho * 256))
+	th := uint16(math.Round(wrapDegrees(theta) / 360 * 65536))
+	return []byte{byte(r >> 8), byte(r), byte(th >> 8), byte(th)}
+}
+
+// encodeMode3A packs a 12-bit octal Mode-3/A code into the low 12 bits
+// of a 2-byte field; the top 4 validity/garble/spare bits are left 0.
+func encodeMode3A(code uint16) []byte {
+	v := code & 0x0FFF
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// encodeFlightLevel packs a flight level into a signed 2-byte field
+// with an LSB of 1/4 FL.
+func encodeFlightLevel(fl float64) []byte {
+	v := int16(math.Round(fl * 4))
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// encodeTrackNumber12 packs a track number into the low 12 bits of a
+// 2-byte field, as CAT048's I161 does.
+func encodeTrackNumber12(tn uint16) []byte {
+	v := tn & 0x0FFF
+	return []byte{byte(v >> 8), byte(v)}
+}
+
+// encodeTrackNumber16 packs a track number into a full 2-byte field, as
+// CAT062's I040 does.
+func encodeTrackNumber16(tn uint16) []byte {
+	return []byte{byte(tn >> 8), byte(tn)}
+}
+
+// encodeAircraftAddress packs a 24-bit ICAO aircraft address into 3
+// bytes.
+func encodeAircraftAddress(addr uint32) []byte {
+	return []byte{byte(addr >> 16), byte(addr >> 8), byte(addr)}
+}
+
+// encodeGroundVector packs ground speed (NM/s, LSB 2^-14 NM/s) and
+// heading (degrees, LSB 360/65536 deg) into a 4-byte field.
+func encodeGroundVector(speed, heading float64) []byte {
+	sp := uint16(math.Round(speed * 16384))
+	hd := uint16(math.Round(wrapDegrees(heading) / 360 * 65536))
+	return []byte{byte(sp >> 8), byte(sp), byte(hd >> 8), byte(hd)}
+}
+
+// encodeWGS84Position packs latitude and longitude (degrees) into two
+// signed 4-byte fields with an LSB of 180/2^25 degrees.
+func encodeWGS84Position(lat, lon float64) []byte {
+	const lsb = 180.0 / (1 << 25)
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(int32(math.Round(lat/lsb))))
+	binary.BigEndian.PutUint32(buf[4:8], uint32(int32(math.Round(lon/lsb))))
+	return buf
+}
+
+// encodeCartesianPosition packs X and Y (NM) into two signed 3-byte
+// fields with an LSB of 1/256 NM.
+func encodeCartesianPosition(x, y float64) []byte {
+	const lsb = 1.0 / 256
+	xi := int32(math.Round(x / lsb))
+	yi := int32(math.Round(y / lsb))
+	return []byte{
+		byte(xi >> 16), byte(xi >> 8), byte(xi),
+		byte(yi >> 16), byte(yi >> 8), byte(yi),
+	}
+}
+
+// encodeVelocityCartesian packs Vx and Vy (m/s) into two signed 2-byte
+// fields with an LSB of 0.25 m/s.
+func encodeVelocityCartesian(v Velocity) []byte {
+	const lsb = 0.25
+	vx := int16(math.Round(v.Vx / lsb))
+	vy := int16(math.Round(v.Vy / lsb))
+	return []byte{byte(vx >> 8), byte(vx), byte(vy >> 8), byte(vy)}
+}
+
+// wrapDegrees normalizes a degree value into [0, 360) before it is
+// scaled into an unsigned angular field.
+func wrapDegrees(deg float64) float64 {
+	d := math.Mod(deg, 360)
+	if d < 0 {
+		d += 360
+	}
+	return d
+}
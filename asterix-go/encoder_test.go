@@ -0,0 +1,224 @@
+package asterix
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeCAT048Zero(t *testing.T) {
+	want := []byte{
+		0x30, 0x00, 0x1B, 0xDD, 0x94,
+		0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+
+	got, err := Encode(CAT048Message{DataSource: DataSource{SAC: 0, SIC: 1}})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("unexpected encoding:\n got  % X\n want % X", got, want)
+	}
+}
+
+func TestEncodeCAT048Populated(t *testing.T) {
+	msg := CAT048Message{
+		DataSource:    DataSource{SAC: 10, SIC: 20},
+		TimeOfDay:     100.0,
+		Rho:           123.5,
+		Theta:         90.0,
+		Mode3A:        4095,
+		FlightLevel:   330.0,
+		TrackNumber:   512,
+		TargetAddress: 7472713,
+		GroundSpeed:   0.5,
+		Heading:       180.0,
+	}
+	want := []byte{
+		0x30, 0x00, 0x1B, 0xDD, 0x94,
+		0x0A, 0x14, // I010 SAC/SIC
+		0x00, 0x32, 0x00, // I140 TimeOfDay
+		0x7B, 0x80, 0x40, 0x00, // I040 RHO/THETA
+		0x0F, 0xFF, // I070 Mode3A
+		0x05, 0x28, // I090 FlightLevel
+		0x72, 0x06, 0x49, // I220 TargetAddress
+		0x02, 0x00, // I161 TrackNumber
+		0x20, 0x00, 0x80, 0x00, // I200 GroundSpeed/Heading
+	}
+
+	got, err := Encode(msg)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("unexpected encoding:\n got  % X\n want % X", got, want)
+	}
+
+	gotPtr, err := Encode(&msg)
+	if err != nil {
+		t.Fatalf("Encode(*CAT048Message) failed: %v", err)
+	}
+	if !bytes.Equal(gotPtr, want) {
+		t.Errorf("Encode(*CAT048Message) differs from Encode(CAT048Message):\n got  % X\n want % X", gotPtr, want)
+	}
+}
+
+func TestEncodeCAT062Zero(t *testing.T) {
+	want := []byte{
+		0x3E, 0x00, 0x26, 0xBD, 0x91, 0x44,
+		0x01, 0x02, // I010 SAC/SIC
+		0x00, 0x00, 0x00, // I070 TimeOfTrack
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // I105 lat/lon
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // I100 X/Y
+		0x00, 0x00, 0x00, 0x00, // I185 Vx/Vy
+		0x00, 0x00, // I060 Mode3A
+		0x00, 0x00, // I040 TrackNumber
+		0x00, 0x00, // I136 FlightLevel
+		0x00, 0x00, 0x00, // I080 TargetAddress
+	}
+
+	got, err := Encode(CAT062Message{DataSource: DataSource{SAC: 1, SIC: 2}})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("unexpected encoding:\n got  % X\n want % X", got, want)
+	}
+}
+
+func TestEncodeCAT062Populated(t *testing.T) {
+	msg := CAT062Message{
+		DataSource:    DataSource{SAC: 1, SIC: 2},
+		TrackNumber:   100,
+		TimeOfTrack:   100.0,
+		Latitude:      52.0,
+		Longitude:     4.0,
+		CartesianX:    10.0,
+		CartesianY:    -5.0,
+		TrackVelocity: Velocity{Vx: 120.0, Vy: -30.0},
+		Mode3A:        1234,
+		FlightLevel:   280.0,
+		TargetAddress: 11259375,
+	}
+	want := []byte{
+		0x3E, 0x00, 0x26, 0xBD, 0x91, 0x44,
+		0x01, 0x02, // I010 SAC/SIC
+		0x00, 0x32, 0x00, // I070 TimeOfTrack
+		0x00, 0x93, 0xE9, 0x3F, 0x00, 0x0B, 0x60, 0xB6, // I105 lat/lon
+		0x00, 0x0A, 0x00, 0xFF, 0xFB, 0x00, // I100 X/Y
+		0x01, 0xE0, 0xFF, 0x88, // I185 Vx/Vy
+		0x04, 0xD2, // I060 Mode3A
+		0x00, 0x64, // I040 TrackNumber
+		0x04, 0x60, // I136 FlightLevel
+		0xAB, 0xCD, 0xEF, // I080 TargetAddress
+	}
+
+	got, err := Encode(msg)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("unexpected encoding:\n got  % X\n want % X", got, want)
+	}
+}
+
+func TestEncodeFromMap(t *testing.T) {
+	data := map[string]interface{}{
+		"category": float64(48),
+		"I010":     map[string]interface{}{"SAC": float64(0), "SIC": float64(1)},
+	}
+
+	got, err := Encode(data)
+	if err != nil {
+		t.Fatalf("Encode(map) failed: %v", err)
+	}
+
+	want, err := Encode(CAT048Message{DataSource: DataSource{SAC: 0, SIC: 1}})
+	if err != nil {
+		t.Fatalf("Encode(CAT048Message) failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Encode(map) should match Encode(typed message):\n got  % X\n want % X", got, want)
+	}
+}
+
+func TestEncodeFromMapMissingCategory(t *testing.T) {
+	_, err := Encode(map[string]interface{}{
+		"I010": map[string]interface{}{"SAC": float64(0), "SIC": float64(1)},
+	})
+	if err == nil {
+		t.Error("Encode(map without category) should return an error")
+	}
+}
+
+func TestEncodeFromMapUnsupportedCategory(t *testing.T) {
+	_, err := Encode(map[string]interface{}{"category": float64(1)})
+	if err == nil {
+		t.Error("Encode(map) with an unimplemented category should return an error")
+	}
+}
+
+func TestEncodeUnsupportedType(t *testing.T) {
+	_, err := Encode(42)
+	if err == nil {
+		t.Error("Encode(int) should return an error")
+	}
+}
+
+func TestEncoderWriteRecord(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder()
+
+	n, err := enc.WriteRecord(&buf, CAT062Message{DataSource: DataSource{SAC: 1, SIC: 2}})
+	if err != nil {
+		t.Fatalf("WriteRecord failed: %v", err)
+	}
+	if n != buf.Len() {
+		t.Errorf("WriteRecord returned n=%d, buffer holds %d bytes", n, buf.Len())
+	}
+
+	want, err := Encode(CAT062Message{DataSource: DataSource{SAC: 1, SIC: 2}})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteRecord wrote % x, want % x", buf.Bytes(), want)
+	}
+}
+
+// TestEncodeParseRoundTrip encodes a CAT048 message and feeds the result
+// back through Parse, the way a replay tool or fuzzer would. Parse needs
+// the C engine initialized with the category's XML definitions (via
+// Init), which this sandbox does not ship, so the test skips rather than
+// silently passing when that's unavailable — but once Init succeeds, the
+// round trip itself must hold.
+func TestEncodeParseRoundTrip(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Skipf("skipping round trip: Init failed without an XML config directory: %v", err)
+	}
+
+	data, err := Encode(CAT048Message{DataSource: DataSource{SAC: 0, SIC: 1}})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	records, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse of encoded block failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0].Category != 48 {
+		t.Errorf("expected Category=48, got %d", records[0].Category)
+	}
+
+	var msg CAT048Message
+	if err := records[0].As(&msg); err != nil {
+		t.Fatalf("As(CAT048Message) on the round-tripped record failed: %v", err)
+	}
+	if msg.DataSource != (DataSource{SAC: 0, SIC: 1}) {
+		t.Errorf("round trip changed DataSource: got %+v", msg.DataSource)
+	}
+}
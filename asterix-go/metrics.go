@@ -0,0 +1,84 @@
+package asterix
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics receives counters and a couple of histograms from every parse
+// call, so operators running long-lived ingest pipelines can wire them
+// into Prometheus, statsd, or OpenTelemetry without patching this
+// module. Parse, ParseWithOptions, and ParseWithOffset call the
+// installed Metrics around the cgo boundary, so latencies reflect the
+// true C++ engine work, which is otherwise invisible to Go profilers.
+type Metrics interface {
+	// IncRecords increments the count of records decoded for category
+	// cat.
+	IncRecords(cat uint8)
+
+	// IncParseError increments the count of parse failures, tagged with
+	// a short, stable kind: "not_initialized", "invalid_data",
+	// "too_large", "memory", or "engine".
+	IncParseError(kind string)
+
+	// ObserveParseLatency records how long one parse call spent in the
+	// C++ engine.
+	ObserveParseLatency(d time.Duration)
+
+	// ObserveBlockSize records the size in bytes of the data a parse
+	// call decoded: all of data for Parse and ParseWithOptions, and the
+	// reported BytesConsumed for ParseWithOffset.
+	ObserveBlockSize(n int)
+}
+
+// noopMetrics is the default Metrics sink: every method is a no-op.
+type noopMetrics struct{}
+
+func (noopMetrics) IncRecords(uint8)                  {}
+func (noopMetrics) IncParseError(string)              {}
+func (noopMetrics) ObserveParseLatency(time.Duration) {}
+func (noopMetrics) ObserveBlockSize(int)              {}
+
+var (
+	metricsMu sync.RWMutex
+	metrics   Metrics = noopMetrics{}
+)
+
+// SetMetrics installs m as the package-wide metrics sink for every
+// subsequent parse call. Passing nil restores the no-op default.
+// SetMetrics is safe to call concurrently with parsing.
+func SetMetrics(m Metrics) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if m == nil {
+		m = noopMetrics{}
+	}
+	metrics = m
+}
+
+// currentMetrics returns the currently installed Metrics sink.
+func currentMetrics() Metrics {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	return metrics
+}
+
+// recordParseError reports a parse failure of the given kind to the
+// installed metrics sink and returns err unchanged, so call sites can
+// write `return recordParseError("kind", err)`.
+func recordParseError(kind string, err error) error {
+	currentMetrics().IncParseError(kind)
+	return err
+}
+
+// recordParseSuccess reports a successful parse to the installed
+// metrics sink: the blocks's size, how long the C++ engine spent on it,
+// and one IncRecords call per record produced, tagged with its category.
+func recordParseSuccess(blockSize int, elapsed time.Duration, records []Record) {
+	m := currentMetrics()
+	m.ObserveBlockSize(blockSize)
+	m.ObserveParseLatency(elapsed)
+	for _, rec := range records {
+		m.IncRecords(rec.Category)
+	}
+}
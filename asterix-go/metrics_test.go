@@ -0,0 +1,83 @@
+package asterix
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	records    map[uint8]int
+	errors     map[string]int
+	latencies  []time.Duration
+	blockSizes []int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		records: make(map[uint8]int),
+		errors:  make(map[string]int),
+	}
+}
+
+func (m *recordingMetrics) IncRecords(cat uint8) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[cat]++
+}
+
+func (m *recordingMetrics) IncParseError(kind string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors[kind]++
+}
+
+func (m *recordingMetrics) ObserveParseLatency(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latencies = append(m.latencies, d)
+}
+
+func (m *recordingMetrics) ObserveBlockSize(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blockSizes = append(m.blockSizes, n)
+}
+
+func TestSetMetricsRecordsParseError(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	rm := newRecordingMetrics()
+	SetMetrics(rm)
+	defer SetMetrics(nil)
+
+	if _, err := Parse(nil); err == nil {
+		t.Fatal("Parse(nil) should return an error")
+	}
+
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.errors["invalid_data"] != 1 {
+		t.Errorf("expected 1 invalid_data error, got %d", rm.errors["invalid_data"])
+	}
+}
+
+func TestSetMetricsNilRestoresNoop(t *testing.T) {
+	SetMetrics(newRecordingMetrics())
+	SetMetrics(nil)
+
+	if _, ok := currentMetrics().(noopMetrics); !ok {
+		t.Errorf("expected noopMetrics after SetMetrics(nil), got %T", currentMetrics())
+	}
+}
+
+func TestNoopMetricsDoesNotPanic(t *testing.T) {
+	var m Metrics = noopMetrics{}
+	m.IncRecords(48)
+	m.IncParseError("engine")
+	m.ObserveParseLatency(time.Millisecond)
+	m.ObserveBlockSize(128)
+}
@@ -0,0 +1,343 @@
+// Package net provides a live-feed receiver for ASTERIX data transported
+// over UDP multicast, the dominant delivery mode for surveillance data
+// from radars and SMR/MLAT sensors in operational ATM networks. It sits
+// alongside the byte-slice oriented asterix.Parse/ParseWithOffset API,
+// which has no notion of sockets or multicast groups.
+package net
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+
+	asterix "github.com/montge/asterix/asterix-go"
+)
+
+// defaultReadBufferSize is used when Config.ReadBufferSize is zero. It is
+// sized well above the largest realistic ASTERIX UDP datagram.
+const defaultReadBufferSize = 64 * 1024
+
+// Handler receives a decoded Record, or a non-nil err if the datagram that
+// produced it failed to parse. Handler is called from the read loop of
+// the socket that received the datagram, so it must not block for long.
+type Handler func(rec asterix.Record, err error)
+
+// RawCallback is invoked with the original datagram bytes and the sender
+// address before the payload is handed to the parser, so callers can
+// persist raw traffic alongside decoded records. data is only valid for
+// the duration of the call.
+type RawCallback func(src *net.UDPAddr, data []byte)
+
+// Group describes a single multicast feed to join.
+type Group struct {
+	// Addr is the multicast group and port, e.g. "239.1.1.1:10001".
+	Addr string
+
+	// Interface restricts the join to a specific network interface. If
+	// nil, the system chooses a default multicast interface.
+	Interface *net.Interface
+
+	// Sources restricts reception to source-specific multicast (SSM)
+	// from these source addresses. If empty, any-source multicast (ASM)
+	// is used instead.
+	Sources []net.IP
+}
+
+// Config configures a MulticastReceiver.
+type Config struct {
+	// Groups lists the multicast groups to join. At least one is
+	// required.
+	Groups []Group
+
+	// ReadBufferSize sets the socket receive buffer size in bytes via
+	// SetReadBuffer. Zero uses defaultReadBufferSize.
+	ReadBufferSize int
+
+	// RawCallback, if set, is called with every datagram's raw bytes
+	// before decoding.
+	RawCallback RawCallback
+}
+
+// SourceStats holds per-source packet accounting for one multicast group.
+type SourceStats struct {
+	// Packets is the number of datagrams received from this source.
+	Packets uint64
+
+	// Records is the number of ASTERIX records successfully decoded
+	// from this source's datagrams.
+	Records uint64
+
+	// Errors is the number of datagrams from this source that failed
+	// to parse.
+	Errors uint64
+}
+
+// Stats is a snapshot of receiver accounting.
+type Stats struct {
+	// BySource maps source IP (string form) to per-source counters,
+	// aggregated across all joined groups.
+	BySource map[string]SourceStats
+
+	// Dropped is the number of datagrams the kernel reports as dropped
+	// before delivery, aggregated across sockets that support
+	// SO_RXQ_OVFL (Linux only, via a recvmsg control message read
+	// alongside each datagram). It is always zero on other platforms.
+	Dropped uint64
+}
+
+type groupConn struct {
+	// groups lists every Group joined on conn. Groups that resolve to the
+	// same network family and port share one socket, so this can hold
+	// more than one entry; see New.
+	groups []Group
+	conn   *net.UDPConn
+
+	// dropped is this socket's cumulative SO_RXQ_OVFL count, as last
+	// reported by readDatagram. It is updated and read atomically since
+	// Stats can be called concurrently with the read loop.
+	dropped uint64
+}
+
+// MulticastReceiver joins one or more multicast groups and dispatches
+// decoded ASTERIX records to a Handler.
+type MulticastReceiver struct {
+	cfg   Config
+	conns []*groupConn
+
+	mu       sync.Mutex
+	bySource map[string]*SourceStats
+
+	wg       sync.WaitGroup
+	stopCh   chan struct{}
+	closeOne sync.Once
+}
+
+// portKey identifies one underlying socket: groups that resolve to the
+// same address family and port are joined on a single shared socket,
+// since a plain net.ListenUDP on an already-bound port fails with
+// "address already in use" — and sharing one port across feeds,
+// distinguished only by multicast group address, is the normal ATM
+// deployment this package targets.
+type portKey struct {
+	family string // "udp4" or "udp6"
+	port   int
+}
+
+// New joins every group in cfg and returns a receiver ready to Start.
+// Joining happens eagerly so that configuration errors (bad address,
+// missing interface, unsupported SSM source) surface before Start.
+func New(cfg Config) (*MulticastReceiver, error) {
+	if len(cfg.Groups) == 0 {
+		return nil, errors.New("asterix/net: at least one group is required")
+	}
+	if cfg.ReadBufferSize <= 0 {
+		cfg.ReadBufferSize = defaultReadBufferSize
+	}
+
+	m := &MulticastReceiver{
+		cfg:      cfg,
+		bySource: make(map[string]*SourceStats),
+		stopCh:   make(chan struct{}),
+	}
+
+	byPort := make(map[portKey]*groupConn)
+	for _, g := range cfg.Groups {
+		gaddr, err := net.ResolveUDPAddr("udp", g.Addr)
+		if err != nil {
+			m.closeConns()
+			return nil, fmt.Errorf("asterix/net: resolve %s: %w", g.Addr, err)
+		}
+		family := "udp4"
+		if gaddr.IP.To4() == nil {
+			family = "udp6"
+		}
+		key := portKey{family: family, port: gaddr.Port}
+
+		gc, ok := byPort[key]
+		if !ok {
+			conn, err := net.ListenUDP(family, &net.UDPAddr{Port: gaddr.Port})
+			if err != nil {
+				m.closeConns()
+				return nil, fmt.Errorf("asterix/net: listen on port %d: %w", gaddr.Port, err)
+			}
+			if err := conn.SetReadBuffer(cfg.ReadBufferSize); err != nil {
+				conn.Close()
+				m.closeConns()
+				return nil, fmt.Errorf("asterix/net: set read buffer for port %d: %w", gaddr.Port, err)
+			}
+			enableRxqOverflow(conn)
+			gc = &groupConn{conn: conn}
+			byPort[key] = gc
+			m.conns = append(m.conns, gc)
+		}
+
+		if err := joinGroup(gc.conn, family, gaddr, g); err != nil {
+			m.closeConns()
+			return nil, fmt.Errorf("asterix/net: join %s: %w", g.Addr, err)
+		}
+		gc.groups = append(gc.groups, g)
+	}
+
+	return m, nil
+}
+
+// joinGroup issues the IGMP/MLD join(s) for g against the already-bound
+// conn, which may already be carrying other groups on the same port.
+func joinGroup(conn *net.UDPConn, family string, gaddr *net.UDPAddr, g Group) error {
+	if family == "udp4" {
+		pc := ipv4.NewPacketConn(conn)
+		if len(g.Sources) == 0 {
+			return pc.JoinGroup(g.Interface, gaddr)
+		}
+		for _, src := range g.Sources {
+			if err := pc.JoinSourceSpecificGroup(g.Interface, gaddr, &net.UDPAddr{IP: src}); err != nil {
+				return fmt.Errorf("join source %s: %w", src, err)
+			}
+		}
+		return nil
+	}
+
+	pc := ipv6.NewPacketConn(conn)
+	if len(g.Sources) == 0 {
+		return pc.JoinGroup(g.Interface, gaddr)
+	}
+	for _, src := range g.Sources {
+		if err := pc.JoinSourceSpecificGroup(g.Interface, gaddr, &net.UDPAddr{IP: src}); err != nil {
+			return fmt.Errorf("join source %s: %w", src, err)
+		}
+	}
+	return nil
+}
+
+// Start begins reading datagrams from every socket on its own goroutine
+// (groups sharing a port share one socket and one goroutine), decoding
+// each payload and passing the result to handler.
+// Start returns immediately; call Close to stop.
+func (m *MulticastReceiver) Start(handler Handler) error {
+	if handler == nil {
+		return errors.New("asterix/net: handler must not be nil")
+	}
+
+	for _, gc := range m.conns {
+		m.wg.Add(1)
+		go m.readLoop(gc, handler)
+	}
+	return nil
+}
+
+func (m *MulticastReceiver) readLoop(gc *groupConn, handler Handler) {
+	defer m.wg.Done()
+
+	buf := make([]byte, m.cfg.ReadBufferSize)
+	for {
+		n, src, overflow, err := readDatagram(gc.conn, buf)
+		select {
+		case <-m.stopCh:
+			return
+		default:
+		}
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+
+		data := buf[:n]
+		if m.cfg.RawCallback != nil {
+			m.cfg.RawCallback(src, data)
+		}
+
+		m.recordPacket(src)
+		atomic.StoreUint64(&gc.dropped, overflow)
+
+		records, parseErr := asterix.ParseWithOptions(data, true)
+		if parseErr != nil {
+			m.recordError(src)
+			handler(asterix.Record{}, parseErr)
+			continue
+		}
+		for _, rec := range records {
+			m.recordDecoded(src)
+			handler(rec, nil)
+		}
+	}
+}
+
+func (m *MulticastReceiver) recordPacket(src *net.UDPAddr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.statsFor(src)
+	s.Packets++
+}
+
+func (m *MulticastReceiver) recordDecoded(src *net.UDPAddr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.statsFor(src)
+	s.Records++
+}
+
+func (m *MulticastReceiver) recordError(src *net.UDPAddr) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := m.statsFor(src)
+	s.Errors++
+}
+
+// statsFor returns the SourceStats for src, creating it if needed. Caller
+// must hold m.mu.
+func (m *MulticastReceiver) statsFor(src *net.UDPAddr) *SourceStats {
+	key := src.IP.String()
+	s, ok := m.bySource[key]
+	if !ok {
+		s = &SourceStats{}
+		m.bySource[key] = s
+	}
+	return s
+}
+
+// Stats returns a snapshot of per-source counters and the aggregate
+// dropped-packet count reported by the kernel.
+func (m *MulticastReceiver) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	bySource := make(map[string]SourceStats, len(m.bySource))
+	for k, v := range m.bySource {
+		bySource[k] = *v
+	}
+
+	var dropped uint64
+	for _, gc := range m.conns {
+		dropped += atomic.LoadUint64(&gc.dropped)
+	}
+
+	return Stats{
+		BySource: bySource,
+		Dropped:  dropped,
+	}
+}
+
+// Close stops all read loops and releases the underlying sockets. It
+// blocks until every read loop has exited. Close is safe to call more
+// than once, including concurrently; only the first call does any work.
+func (m *MulticastReceiver) Close() error {
+	m.closeOne.Do(func() {
+		close(m.stopCh)
+		m.closeConns()
+		m.wg.Wait()
+	})
+	return nil
+}
+
+func (m *MulticastReceiver) closeConns() {
+	for _, gc := range m.conns {
+		gc.conn.Close()
+	}
+}
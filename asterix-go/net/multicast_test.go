@@ -0,0 +1,57 @@
+package net
+
+import "testing"
+
+func TestNewRequiresGroups(t *testing.T) {
+	_, err := New(Config{})
+	if err == nil {
+		t.Error("New(Config{}) should return an error when no groups are configured")
+	}
+}
+
+func TestNewRejectsInvalidAddr(t *testing.T) {
+	_, err := New(Config{Groups: []Group{{Addr: "not-an-address"}}})
+	if err == nil {
+		t.Error("New() should return an error for an unresolvable group address")
+	}
+}
+
+func TestStartRequiresHandler(t *testing.T) {
+	m := &MulticastReceiver{stopCh: make(chan struct{})}
+	if err := m.Start(nil); err == nil {
+		t.Error("Start(nil) should return an error")
+	}
+}
+
+func TestNewSharesSocketAcrossGroupsOnSamePort(t *testing.T) {
+	// Two groups on the same port is the normal ATM deployment: several
+	// feeds distinguished only by multicast group address. A naive
+	// net.ListenUDP per group would fail the second bind with "address
+	// already in use"; New must instead share one socket per port.
+	m, err := New(Config{Groups: []Group{
+		{Addr: "239.1.1.1:31000"},
+		{Addr: "239.1.1.2:31000"},
+	}})
+	if err != nil {
+		t.Fatalf("New() with two groups on the same port failed: %v", err)
+	}
+	defer m.Close()
+
+	if len(m.conns) != 1 {
+		t.Errorf("expected groups sharing a port to share one socket, got %d", len(m.conns))
+	}
+}
+
+func TestStatsEmptySnapshot(t *testing.T) {
+	m := &MulticastReceiver{
+		bySource: make(map[string]*SourceStats),
+		stopCh:   make(chan struct{}),
+	}
+	stats := m.Stats()
+	if len(stats.BySource) != 0 {
+		t.Errorf("expected empty BySource, got %v", stats.BySource)
+	}
+	if stats.Dropped != 0 {
+		t.Errorf("expected Dropped=0, got %d", stats.Dropped)
+	}
+}
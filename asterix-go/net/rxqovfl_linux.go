@@ -0,0 +1,88 @@
+//go:build linux
+
+package net
+
+import (
+	"encoding/binary"
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// unixSORxqOvfl is SO_RXQ_OVFL, which is not exposed by the syscall
+// package on all architectures.
+const unixSORxqOvfl = unix.SO_RXQ_OVFL
+
+// enableRxqOverflow requests SO_RXQ_OVFL on conn so the kernel attaches a
+// drop counter to each datagram's control messages. It is best-effort:
+// failures are ignored since overflow accounting is a diagnostic, not a
+// correctness requirement.
+func enableRxqOverflow(conn *net.UDPConn) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return
+	}
+	_ = raw.Control(func(fd uintptr) {
+		_ = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unixSORxqOvfl, 1)
+	})
+}
+
+// readDatagram reads one datagram from conn via recvmsg, so that the
+// SO_RXQ_OVFL control message requested by enableRxqOverflow can be
+// decoded alongside the payload. overflow is the cumulative number of
+// datagrams the kernel has dropped for this socket since SO_RXQ_OVFL was
+// enabled, or 0 if the kernel did not attach the control message (e.g.
+// enableRxqOverflow failed).
+func readDatagram(conn *net.UDPConn, buf []byte) (n int, src *net.UDPAddr, overflow uint64, err error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, nil, 0, err
+	}
+
+	oob := make([]byte, unix.CmsgSpace(4))
+	var (
+		oobn    int
+		from    unix.Sockaddr
+		recvErr error
+	)
+	ctrlErr := raw.Read(func(fd uintptr) bool {
+		n, oobn, _, from, recvErr = unix.Recvmsg(int(fd), buf, oob, 0)
+		return recvErr != unix.EAGAIN
+	})
+	if ctrlErr != nil {
+		return 0, nil, 0, ctrlErr
+	}
+	if recvErr != nil {
+		return 0, nil, 0, recvErr
+	}
+
+	return n, sockaddrToUDPAddr(from), parseRxqOverflow(oob[:oobn]), nil
+}
+
+// sockaddrToUDPAddr converts the unix.Sockaddr recvmsg fills in for the
+// datagram's source into the *net.UDPAddr the rest of this package uses.
+func sockaddrToUDPAddr(sa unix.Sockaddr) *net.UDPAddr {
+	switch a := sa.(type) {
+	case *unix.SockaddrInet4:
+		return &net.UDPAddr{IP: net.IP(a.Addr[:]), Port: a.Port}
+	case *unix.SockaddrInet6:
+		return &net.UDPAddr{IP: net.IP(a.Addr[:]), Port: a.Port}
+	default:
+		return nil
+	}
+}
+
+// parseRxqOverflow scans recvmsg's control messages for SO_RXQ_OVFL and
+// returns its uint32 payload, or 0 if the control message is absent.
+func parseRxqOverflow(oob []byte) uint64 {
+	cmsgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return 0
+	}
+	for _, cmsg := range cmsgs {
+		if cmsg.Header.Level == unix.SOL_SOCKET && int(cmsg.Header.Type) == unixSORxqOvfl && len(cmsg.Data) >= 4 {
+			return uint64(binary.LittleEndian.Uint32(cmsg.Data))
+		}
+	}
+	return 0
+}
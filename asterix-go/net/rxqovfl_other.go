@@ -0,0 +1,15 @@
+//go:build !linux
+
+package net
+
+import "net"
+
+// enableRxqOverflow is a no-op on platforms without SO_RXQ_OVFL.
+func enableRxqOverflow(conn *net.UDPConn) {}
+
+// readDatagram reads one datagram from conn. Platforms other than Linux
+// have no SO_RXQ_OVFL equivalent wired up here, so overflow is always 0.
+func readDatagram(conn *net.UDPConn, buf []byte) (n int, src *net.UDPAddr, overflow uint64, err error) {
+	n, src, err = conn.ReadFromUDP(buf)
+	return n, src, 0, err
+}
@@ -0,0 +1,554 @@
+package asterix
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// pcap/pcapng magic numbers. See https://wiki.wireshark.org/Development/LibpcapFileFormat
+// and https://pcapng.com/.
+const (
+	pcapMagicMicros     = 0xa1b2c3d4
+	pcapMagicMicrosSwap = 0xd4c3b2a1
+	pcapMagicNanos      = 0xa1b23c4d
+	pcapMagicNanosSwap  = 0x4d3cb2a1
+	pcapngMagic         = 0x0a0d0d0a
+)
+
+// dltEN10MB is the libpcap LINKTYPE_ETHERNET value. It is the only link
+// type ParsePCAP currently knows how to strip; captures using any other
+// link type return an error.
+const dltEN10MB = 1
+
+// PCAPFilter restricts a capture read to packets matching a destination
+// UDP port and/or destination address (typically a multicast group),
+// mirroring a simple BPF "udp and dst port X and dst host Y" expression.
+// A zero field is not checked.
+type PCAPFilter struct {
+	DstPort uint16
+	DstAddr net.IP
+}
+
+func (f PCAPFilter) matches(dstPort uint16, dstAddr net.IP) bool {
+	if f.DstPort != 0 && f.DstPort != dstPort {
+		return false
+	}
+	if len(f.DstAddr) != 0 && !f.DstAddr.Equal(dstAddr) {
+		return false
+	}
+	return true
+}
+
+func matchesAny(filters []PCAPFilter, dstPort uint16, dstAddr net.IP) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if f.matches(dstPort, dstAddr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePCAP reads a libpcap or pcapng capture from r, extracts the UDP
+// payload of every Ethernet/IP/UDP packet, and parses each payload as one
+// or more ASTERIX blocks. The capture's per-packet timestamp replaces
+// Record.Timestamp on every record it produces, since operational
+// ASTERIX analysis relies on when a packet was captured rather than when
+// it happened to be parsed. This replaces the common workaround of
+// shelling out to tshark before feeding bytes into Parse.
+//
+// If filters are given, only packets matching at least one of them are
+// decoded; this lets callers point ParsePCAP at large mixed captures and
+// pull out just one feed.
+func ParsePCAP(r io.Reader, filters ...PCAPFilter) ([]Record, error) {
+	sp, err := NewPCAPStreamParser(r, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for {
+		recs, err := sp.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, recs...)
+	}
+}
+
+// PCAPStreamParser reads packets from a pcap or pcapng capture one at a
+// time, so large captures can be processed without loading every packet
+// into memory up front.
+type PCAPStreamParser struct {
+	reader  pcapPacketReader
+	filters []PCAPFilter
+}
+
+// NewPCAPStreamParser detects whether r holds a classic pcap or pcapng
+// capture and returns a parser positioned to read its first packet.
+func NewPCAPStreamParser(r io.Reader, filters ...PCAPFilter) (*PCAPStreamParser, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := peekMagic(br)
+	if err != nil {
+		return nil, fmt.Errorf("asterix: reading capture magic: %w", err)
+	}
+
+	var pr pcapPacketReader
+	switch magic {
+	case pcapMagicMicros, pcapMagicMicrosSwap, pcapMagicNanos, pcapMagicNanosSwap:
+		pr, err = newClassicPCAPReader(br)
+	case pcapngMagic:
+		pr, err = newPCAPNGReader(br)
+	default:
+		return nil, fmt.Errorf("asterix: unrecognized capture magic 0x%08x", magic)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &PCAPStreamParser{reader: pr, filters: filters}, nil
+}
+
+func peekMagic(br *bufio.Reader) (uint32, error) {
+	b, err := br.Peek(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// Next returns the ASTERIX records decoded from the next matching packet
+// in the capture, with Timestamp set to the packet's capture time. It
+// returns io.EOF once the capture is exhausted.
+func (p *PCAPStreamParser) Next() ([]Record, error) {
+	for {
+		payload, ts, err := p.reader.readPacket()
+		if err != nil {
+			return nil, err
+		}
+		if payload == nil {
+			continue // no frame data; keep scanning
+		}
+
+		udpPayload, dstPort, dstAddr, ok := stripUDP(payload)
+		if !ok {
+			continue
+		}
+		if !matchesAny(p.filters, dstPort, dstAddr) {
+			continue
+		}
+		if len(udpPayload) == 0 {
+			continue
+		}
+
+		records, err := ParseWithOptions(udpPayload, true)
+		if err != nil {
+			continue // not a valid ASTERIX block; skip rather than abort the capture
+		}
+		for i := range records {
+			records[i].Timestamp = ts
+		}
+		if len(records) > 0 {
+			return records, nil
+		}
+	}
+}
+
+// pcapPacketReader yields successive link-layer frames with their capture
+// timestamp, or io.EOF when the capture is exhausted.
+type pcapPacketReader interface {
+	// readPacket returns the next captured link-layer frame, whole, and
+	// its capture timestamp. Frames from non-Ethernet interfaces are
+	// skipped internally; non-UDP frames are returned like any other and
+	// left for Next to drop once stripUDP rejects them.
+	readPacket() (payload []byte, ts time.Time, err error)
+}
+
+// stripUDP strips the Ethernet (optionally VLAN-tagged) and IPv4/IPv6
+// headers from frame and returns the UDP payload along with the
+// destination port and address, which callers use for BPF-style
+// filtering.
+func stripUDP(frame []byte) (payload []byte, dstPort uint16, dstAddr net.IP, ok bool) {
+	const ethHeaderLen = 14
+	if len(frame) < ethHeaderLen {
+		return nil, 0, nil, false
+	}
+
+	etherType := binary.BigEndian.Uint16(frame[12:14])
+	off := ethHeaderLen
+	for etherType == 0x8100 || etherType == 0x88a8 { // VLAN / QinQ tag
+		if len(frame) < off+4 {
+			return nil, 0, nil, false
+		}
+		etherType = binary.BigEndian.Uint16(frame[off+2 : off+4])
+		off += 4
+	}
+	if len(frame) <= off {
+		return nil, 0, nil, false
+	}
+
+	var proto byte
+	var ipPayload []byte
+
+	switch etherType {
+	case 0x0800: // IPv4
+		ipHeader := frame[off:]
+		if len(ipHeader) < 20 {
+			return nil, 0, nil, false
+		}
+		ihl := int(ipHeader[0]&0x0f) * 4
+		if ihl < 20 || len(ipHeader) < ihl {
+			return nil, 0, nil, false
+		}
+		proto = ipHeader[9]
+		dstAddr = net.IP(append([]byte(nil), ipHeader[16:20]...))
+		ipPayload = ipHeader[ihl:]
+	case 0x86dd: // IPv6
+		ipHeader := frame[off:]
+		if len(ipHeader) < 40 {
+			return nil, 0, nil, false
+		}
+		proto = ipHeader[6]
+		dstAddr = net.IP(append([]byte(nil), ipHeader[24:40]...))
+		ipPayload = ipHeader[40:]
+	default:
+		return nil, 0, nil, false
+	}
+
+	const udpHeaderLen = 8
+	if proto != 17 || len(ipPayload) < udpHeaderLen {
+		return nil, 0, nil, false
+	}
+	dstPort = binary.BigEndian.Uint16(ipPayload[2:4])
+	return ipPayload[udpHeaderLen:], dstPort, dstAddr, true
+}
+
+// classicPCAPReader reads the original libpcap capture format.
+type classicPCAPReader struct {
+	br        *bufio.Reader
+	byteOrder binary.ByteOrder
+	nanos     bool
+	linkType  uint32
+	snaplen   uint32
+}
+
+func newClassicPCAPReader(br *bufio.Reader) (*classicPCAPReader, error) {
+	var hdr [24]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return nil, fmt.Errorf("asterix: reading pcap global header: %w", err)
+	}
+
+	magic := binary.BigEndian.Uint32(hdr[0:4])
+	var byteOrder binary.ByteOrder
+	var nanos bool
+	switch magic {
+	case pcapMagicMicros:
+		byteOrder, nanos = binary.BigEndian, false
+	case pcapMagicMicrosSwap:
+		byteOrder, nanos = binary.LittleEndian, false
+	case pcapMagicNanos:
+		byteOrder, nanos = binary.BigEndian, true
+	case pcapMagicNanosSwap:
+		byteOrder, nanos = binary.LittleEndian, true
+	default:
+		return nil, fmt.Errorf("asterix: unrecognized pcap magic 0x%08x", magic)
+	}
+
+	linkType := byteOrder.Uint32(hdr[20:24])
+	if linkType != dltEN10MB {
+		return nil, fmt.Errorf("asterix: unsupported pcap link type %d (only Ethernet is supported)", linkType)
+	}
+	snaplen := byteOrder.Uint32(hdr[16:20])
+
+	return &classicPCAPReader{br: br, byteOrder: byteOrder, nanos: nanos, linkType: linkType, snaplen: snaplen}, nil
+}
+
+func (c *classicPCAPReader) readPacket() ([]byte, time.Time, error) {
+	for {
+		var rec [16]byte
+		if _, err := io.ReadFull(c.br, rec[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, time.Time{}, err
+		}
+
+		tsSec := c.byteOrder.Uint32(rec[0:4])
+		tsFrac := c.byteOrder.Uint32(rec[4:8])
+		inclLen := c.byteOrder.Uint32(rec[8:12])
+
+		// inclLen comes straight from the capture file; a corrupt or
+		// truncated one can claim a multi-GB length and trigger a huge
+		// allocation long before io.ReadFull would fail on its own. The
+		// global header's snaplen is an upper bound on any single
+		// packet's captured length, so reject anything past it — but
+		// snaplen is itself attacker-controlled and a crafted header can
+		// set it to 0 (historically used to mean "unset" by some
+		// writers), so also enforce the same fixed ceiling used for the
+		// pcapng path regardless of what snaplen says.
+		if inclLen > maxPCAPRecordLen {
+			return nil, time.Time{}, fmt.Errorf("asterix: pcap record length %d exceeds sanity ceiling %d", inclLen, maxPCAPRecordLen)
+		}
+		if c.snaplen != 0 && inclLen > c.snaplen {
+			return nil, time.Time{}, fmt.Errorf("asterix: pcap record length %d exceeds snaplen %d", inclLen, c.snaplen)
+		}
+
+		data := make([]byte, inclLen)
+		if _, err := io.ReadFull(c.br, data); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, time.Time{}, err
+		}
+
+		var ts time.Time
+		if c.nanos {
+			ts = time.Unix(int64(tsSec), int64(tsFrac)).UTC()
+		} else {
+			ts = time.UnixMicro(int64(tsSec)*1_000_000 + int64(tsFrac)).UTC()
+		}
+
+		return data, ts, nil
+	}
+}
+
+// pcapngInterface tracks the per-interface state needed to interpret an
+// Enhanced Packet Block: its link type and timestamp resolution.
+type pcapngInterface struct {
+	linkType uint16
+	tsUnitNs int64 // nanoseconds per timestamp tick
+}
+
+// pcapngReader reads the modern pcapng capture format, handling Section
+// Header Blocks, Interface Description Blocks, and Enhanced Packet
+// Blocks. Other block types are skipped.
+type pcapngReader struct {
+	br         *bufio.Reader
+	byteOrder  binary.ByteOrder
+	interfaces []pcapngInterface
+}
+
+const (
+	blockTypeSectionHeader = 0x0A0D0D0A
+	blockTypeInterfaceDesc = 0x00000001
+	blockTypeEnhancedPkt   = 0x00000006
+)
+
+// maxPCAPRecordLen caps a single classic pcap record's captured length,
+// independent of the capture's own (attacker-controlled) snaplen field —
+// see the check in classicPCAPReader.readPacket.
+const maxPCAPRecordLen = 16 * 1024 * 1024
+
+// maxPCAPNGBlockLen caps a single pcapng block body, guarding against a
+// corrupt or truncated capture whose blockLen field claims a huge size
+// and would otherwise trigger a huge allocation before io.ReadFull ever
+// gets a chance to fail. pcapng has no per-block snaplen to check
+// against, so this is a fixed ceiling well above any realistic block
+// (an Enhanced Packet Block holding a jumbo frame plus options).
+const maxPCAPNGBlockLen = 16 * 1024 * 1024
+
+func newPCAPNGReader(br *bufio.Reader) (*pcapngReader, error) {
+	r := &pcapngReader{br: br}
+	if err := r.readSectionHeader(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (p *pcapngReader) readSectionHeader() error {
+	var hdr [8]byte
+	if _, err := io.ReadFull(p.br, hdr[:]); err != nil {
+		return fmt.Errorf("asterix: reading pcapng section header block: %w", err)
+	}
+	blockType := binary.BigEndian.Uint32(hdr[0:4])
+	if blockType != blockTypeSectionHeader {
+		return fmt.Errorf("asterix: expected pcapng section header block, got type 0x%08x", blockType)
+	}
+
+	var rest [4]byte
+	if _, err := io.ReadFull(p.br, rest[:]); err != nil {
+		return fmt.Errorf("asterix: reading pcapng byte-order magic: %w", err)
+	}
+	switch binary.BigEndian.Uint32(rest[:]) {
+	case 0x1A2B3C4D:
+		p.byteOrder = binary.BigEndian
+	case 0x4D3C2B1A:
+		p.byteOrder = binary.LittleEndian
+	default:
+		return errors.New("asterix: invalid pcapng byte-order magic")
+	}
+	// The block length is written in the section's own byte order.
+	blockLen := p.byteOrder.Uint32(hdr[4:8])
+
+	// Skip the remainder of the block (major/minor version, section
+	// length, options, trailing block length) — we don't need them.
+	remaining := int(blockLen) - 8 - 4
+	if remaining < 0 {
+		return errors.New("asterix: malformed pcapng section header block")
+	}
+	if _, err := io.CopyN(io.Discard, p.br, int64(remaining)); err != nil {
+		return fmt.Errorf("asterix: skipping pcapng section header body: %w", err)
+	}
+	return nil
+}
+
+func (p *pcapngReader) readPacket() ([]byte, time.Time, error) {
+	for {
+		var hdr [8]byte
+		if _, err := io.ReadFull(p.br, hdr[:]); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, time.Time{}, err
+		}
+		blockType := p.byteOrder.Uint32(hdr[0:4])
+		if blockType == blockTypeSectionHeader {
+			// A new section's block length is written in its own byte
+			// order, so take the order from its magic before decoding it.
+			if magic, err := p.br.Peek(4); err == nil {
+				switch binary.BigEndian.Uint32(magic) {
+				case 0x1A2B3C4D:
+					p.byteOrder = binary.BigEndian
+				case 0x4D3C2B1A:
+					p.byteOrder = binary.LittleEndian
+				}
+			}
+		}
+		blockLen := p.byteOrder.Uint32(hdr[4:8])
+		if blockLen < 12 {
+			return nil, time.Time{}, fmt.Errorf("asterix: malformed pcapng block (length %d)", blockLen)
+		}
+		if blockLen-8 > maxPCAPNGBlockLen {
+			return nil, time.Time{}, fmt.Errorf("asterix: pcapng block length %d exceeds sanity ceiling %d", blockLen, maxPCAPNGBlockLen)
+		}
+
+		body := make([]byte, blockLen-8)
+		if _, err := io.ReadFull(p.br, body); err != nil {
+			if err == io.ErrUnexpectedEOF {
+				err = io.EOF
+			}
+			return nil, time.Time{}, err
+		}
+		// body ends with the trailing block-total-length field, which
+		// duplicates hdr[4:8]; strip it since we already have blockLen.
+		body = body[:len(body)-4]
+
+		switch blockType {
+		case blockTypeInterfaceDesc:
+			p.addInterface(body)
+		case blockTypeEnhancedPkt:
+			data, ts, ok := p.decodeEnhancedPacket(body)
+			if ok {
+				return data, ts, nil
+			}
+		case blockTypeSectionHeader:
+			// A new section may change byte order; re-read it like the
+			// initial header (minus the 8 bytes already consumed).
+			if len(body) < 4 {
+				return nil, time.Time{}, errors.New("asterix: malformed pcapng section header block")
+			}
+			switch binary.BigEndian.Uint32(body[0:4]) {
+			case 0x1A2B3C4D:
+				p.byteOrder = binary.BigEndian
+			case 0x4D3C2B1A:
+				p.byteOrder = binary.LittleEndian
+			}
+			p.interfaces = nil
+		}
+		// Any other block type (simple packet, name resolution,
+		// statistics, custom blocks, ...) is skipped.
+	}
+}
+
+func (p *pcapngReader) addInterface(body []byte) {
+	const idbFixedLen = 8 // linktype(2) + reserved(2) + snaplen(4)
+	if len(body) < idbFixedLen {
+		return
+	}
+	linkType := p.byteOrder.Uint16(body[0:2])
+
+	tsUnitNs := int64(1000) // default resolution is microseconds (10^-6 s)
+	opts := body[idbFixedLen:]
+	const ifTsresol = 9
+	for len(opts) >= 4 {
+		optCode := p.byteOrder.Uint16(opts[0:2])
+		optLen := int(p.byteOrder.Uint16(opts[2:4]))
+		padded := (optLen + 3) &^ 3
+		if len(opts) < 4+padded {
+			break
+		}
+		if optCode == ifTsresol && optLen >= 1 {
+			tsUnitNs = tsresolToNanos(opts[4])
+		}
+		if optCode == 0 { // opt_endofopt
+			break
+		}
+		opts = opts[4+padded:]
+	}
+
+	p.interfaces = append(p.interfaces, pcapngInterface{linkType: linkType, tsUnitNs: tsUnitNs})
+}
+
+// tsresolToNanos decodes the if_tsresol option byte into nanoseconds per
+// timestamp tick: the high bit selects a power of 2 vs power of 10 base,
+// and the low 7 bits give the (negative) exponent.
+func tsresolToNanos(b byte) int64 {
+	exp := int64(b & 0x7f)
+	if b&0x80 != 0 {
+		return pow(2, exp, 1_000_000_000)
+	}
+	return pow(10, exp, 1_000_000_000)
+}
+
+// pow computes unit/base^exp for small, non-negative exponents, clamped
+// to a minimum of 1 nanosecond.
+func pow(base, exp, unit int64) int64 {
+	v := unit
+	for i := int64(0); i < exp && v > 1; i++ {
+		v /= base
+	}
+	if v < 1 {
+		v = 1
+	}
+	return v
+}
+
+func (p *pcapngReader) decodeEnhancedPacket(body []byte) ([]byte, time.Time, bool) {
+	const epbFixedLen = 20 // ifid(4) + ts_high(4) + ts_low(4) + caplen(4) + origlen(4)
+	if len(body) < epbFixedLen {
+		return nil, time.Time{}, false
+	}
+
+	ifID := p.byteOrder.Uint32(body[0:4])
+	tsHigh := p.byteOrder.Uint32(body[4:8])
+	tsLow := p.byteOrder.Uint32(body[8:12])
+	capLen := p.byteOrder.Uint32(body[12:16])
+
+	if int(ifID) >= len(p.interfaces) {
+		return nil, time.Time{}, false
+	}
+	iface := p.interfaces[ifID]
+	if iface.linkType != dltEN10MB {
+		return nil, time.Time{}, false
+	}
+	if uint32(len(body)-epbFixedLen) < capLen {
+		return nil, time.Time{}, false
+	}
+
+	ticks := uint64(tsHigh)<<32 | uint64(tsLow)
+	ts := time.Unix(0, int64(ticks)*iface.tsUnitNs).UTC()
+
+	data := body[epbFixedLen : epbFixedLen+int(capLen)]
+	return data, ts, true
+}
@@ -0,0 +1,340 @@
+package asterix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// buildEthIPv4UDP wraps payload in a minimal Ethernet + IPv4 + UDP frame
+// addressed to dstIP:dstPort.
+func buildEthIPv4UDP(dstIP net.IP, dstPort uint16, payload []byte) []byte {
+	udpLen := 8 + len(payload)
+	udp := make([]byte, udpLen)
+	binary.BigEndian.PutUint16(udp[0:2], 12345)       // src port
+	binary.BigEndian.PutUint16(udp[2:4], dstPort)      // dst port
+	binary.BigEndian.PutUint16(udp[4:6], uint16(udpLen)) // length
+	copy(udp[8:], payload)
+
+	ipLen := 20 + udpLen
+	ip := make([]byte, 20)
+	ip[0] = 0x45 // version 4, IHL 5
+	binary.BigEndian.PutUint16(ip[2:4], uint16(ipLen))
+	ip[8] = 64   // TTL
+	ip[9] = 17   // UDP
+	copy(ip[12:16], net.IPv4(10, 0, 0, 1).To4())
+	copy(ip[16:20], dstIP.To4())
+
+	eth := make([]byte, 14)
+	binary.BigEndian.PutUint16(eth[12:14], 0x0800)
+
+	frame := append(eth, ip...)
+	frame = append(frame, udp...)
+	return frame
+}
+
+// buildClassicPCAP assembles a minimal classic pcap capture (big-endian,
+// microsecond resolution, Ethernet link type) containing one frame per
+// given (timestamp, frame) pair.
+func buildClassicPCAP(frames ...[]byte) []byte {
+	var buf bytes.Buffer
+	hdr := make([]byte, 24)
+	binary.BigEndian.PutUint32(hdr[0:4], pcapMagicMicros)
+	binary.BigEndian.PutUint16(hdr[4:6], 2) // version major
+	binary.BigEndian.PutUint16(hdr[6:8], 4) // version minor
+	binary.BigEndian.PutUint32(hdr[16:20], 65535) // snaplen
+	binary.BigEndian.PutUint32(hdr[20:24], dltEN10MB)
+	buf.Write(hdr)
+
+	for i, frame := range frames {
+		rec := make([]byte, 16)
+		binary.BigEndian.PutUint32(rec[0:4], 1700000000+uint32(i)) // ts_sec
+		binary.BigEndian.PutUint32(rec[4:8], 0)                    // ts_usec
+		binary.BigEndian.PutUint32(rec[8:12], uint32(len(frame)))  // incl_len
+		binary.BigEndian.PutUint32(rec[12:16], uint32(len(frame))) // orig_len
+		buf.Write(rec)
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func TestParsePCAPClassic(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	frame := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+	cap := buildClassicPCAP(frame)
+
+	records, err := ParsePCAP(bytes.NewReader(cap))
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	want := time.Unix(1700000000, 0).UTC()
+	if !records[0].Timestamp.Equal(want) {
+		t.Errorf("expected capture timestamp %v, got %v", want, records[0].Timestamp)
+	}
+	if records[0].Category != 48 {
+		t.Errorf("expected Category=48, got %d", records[0].Category)
+	}
+}
+
+func TestParsePCAPFilterByPort(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	match := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+	noMatch := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 20002, sampleCAT062)
+	cap := buildClassicPCAP(match, noMatch)
+
+	records, err := ParsePCAP(bytes.NewReader(cap), PCAPFilter{DstPort: 10001})
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record after filtering, got %d", len(records))
+	}
+	if records[0].Category != 48 {
+		t.Errorf("expected Category=48, got %d", records[0].Category)
+	}
+}
+
+func TestParsePCAPUnrecognizedMagic(t *testing.T) {
+	_, err := ParsePCAP(bytes.NewReader([]byte{0, 1, 2, 3, 4, 5, 6, 7}))
+	if err == nil {
+		t.Error("ParsePCAP should fail on an unrecognized capture magic")
+	}
+}
+
+func TestParsePCAPClassicRejectsOversizedRecord(t *testing.T) {
+	cap := buildClassicPCAP()
+	// Corrupt the one packet record's incl_len field (first byte past the
+	// 24-byte global header, at offset 8 within the 16-byte record
+	// header) to claim a length far beyond the declared snaplen.
+	cap = append(cap, make([]byte, 16)...)
+	binary.BigEndian.PutUint32(cap[24+8:24+12], 0xFFFFFFFF)
+
+	_, err := ParsePCAP(bytes.NewReader(cap))
+	if err == nil {
+		t.Error("ParsePCAP should reject a record whose incl_len exceeds the capture's snaplen")
+	}
+}
+
+func TestParsePCAPClassicRejectsOversizedRecordWithZeroSnaplen(t *testing.T) {
+	cap := buildClassicPCAP()
+	// A crafted or corrupted global header can declare snaplen=0, which
+	// must not be read as "no limit" and bypass the sanity ceiling.
+	binary.BigEndian.PutUint32(cap[16:20], 0)
+	cap = append(cap, make([]byte, 16)...)
+	binary.BigEndian.PutUint32(cap[24+8:24+12], 0xFFFFFFFF)
+
+	_, err := ParsePCAP(bytes.NewReader(cap))
+	if err == nil {
+		t.Error("ParsePCAP should reject an oversized record even when the capture's snaplen is 0")
+	}
+}
+
+// buildPCAPNGOption encodes one pcapng TLV option, padded to a 4-byte
+// boundary as the format requires.
+func buildPCAPNGOption(byteOrder binary.ByteOrder, code uint16, value []byte) []byte {
+	padded := (len(value) + 3) &^ 3
+	buf := make([]byte, 4+padded)
+	byteOrder.PutUint16(buf[0:2], code)
+	byteOrder.PutUint16(buf[2:4], uint16(len(value)))
+	copy(buf[4:], value)
+	return buf
+}
+
+// buildPCAPNGBlock wraps body in a pcapng block header/trailer pair
+// (block type, length, body, repeated length), padding body to a 4-byte
+// boundary as the format requires.
+func buildPCAPNGBlock(byteOrder binary.ByteOrder, blockType uint32, body []byte) []byte {
+	padded := (len(body) + 3) &^ 3
+	body = append(append([]byte(nil), body...), make([]byte, padded-len(body))...)
+
+	blockLen := uint32(12 + len(body))
+	buf := make([]byte, 4+4+len(body)+4)
+	byteOrder.PutUint32(buf[0:4], blockType)
+	byteOrder.PutUint32(buf[4:8], blockLen)
+	copy(buf[8:], body)
+	byteOrder.PutUint32(buf[8+len(body):], blockLen)
+	return buf
+}
+
+// buildPCAPNGSectionHeaderBlock assembles a Section Header Block body
+// (byte-order magic, version, and an "unknown" section length) declaring
+// little-endian as the section's byte order, and wraps it in a block
+// header/trailer written in headerOrder. pcapngReader decodes a Section
+// Header Block's length in the byte order its magic declares, so a
+// well-formed capture passes binary.LittleEndian here.
+func buildPCAPNGSectionHeaderBlock(headerOrder binary.ByteOrder) []byte {
+	body := make([]byte, 16) // byte-order magic(4) + major(2) + minor(2) + section len(8)
+	binary.LittleEndian.PutUint32(body[0:4], 0x1A2B3C4D)
+	binary.LittleEndian.PutUint16(body[4:6], 1)
+	binary.LittleEndian.PutUint16(body[6:8], 0)
+	for i := range body[8:16] {
+		body[8+i] = 0xFF // section length "unknown"
+	}
+	return buildPCAPNGBlock(headerOrder, blockTypeSectionHeader, body)
+}
+
+// buildPCAPNGSectionHeader assembles the first Section Header Block of a
+// capture.
+func buildPCAPNGSectionHeader() []byte {
+	return buildPCAPNGSectionHeaderBlock(binary.LittleEndian)
+}
+
+// buildPCAPNGInterfaceDesc assembles an Interface Description Block
+// advertising dltEN10MB and, if tsresol != 0, an if_tsresol option.
+func buildPCAPNGInterfaceDesc(tsresol byte) []byte {
+	body := make([]byte, 8) // linktype(2) + reserved(2) + snaplen(4)
+	binary.LittleEndian.PutUint16(body[0:2], dltEN10MB)
+	binary.LittleEndian.PutUint32(body[4:8], 65535)
+	if tsresol != 0 {
+		body = append(body, buildPCAPNGOption(binary.LittleEndian, 9, []byte{tsresol})...)
+		body = append(body, buildPCAPNGOption(binary.LittleEndian, 0, nil)...) // opt_endofopt
+	}
+	return buildPCAPNGBlock(binary.LittleEndian, blockTypeInterfaceDesc, body)
+}
+
+// buildPCAPNGEnhancedPacket assembles an Enhanced Packet Block for ifID
+// carrying frame, timestamped tsTicks ticks (in the interface's
+// resolution) since the Unix epoch.
+func buildPCAPNGEnhancedPacket(ifID uint32, tsTicks uint64, frame []byte) []byte {
+	body := make([]byte, 20+len(frame))
+	binary.LittleEndian.PutUint32(body[0:4], ifID)
+	binary.LittleEndian.PutUint32(body[4:8], uint32(tsTicks>>32))
+	binary.LittleEndian.PutUint32(body[8:12], uint32(tsTicks))
+	binary.LittleEndian.PutUint32(body[12:16], uint32(len(frame)))
+	binary.LittleEndian.PutUint32(body[16:20], uint32(len(frame)))
+	copy(body[20:], frame)
+	return buildPCAPNGBlock(binary.LittleEndian, blockTypeEnhancedPkt, body)
+}
+
+func TestParsePCAPNG(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	frame := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+
+	var cap bytes.Buffer
+	cap.Write(buildPCAPNGSectionHeader())
+	cap.Write(buildPCAPNGInterfaceDesc(6)) // 10^-6 s, i.e. microseconds
+	cap.Write(buildPCAPNGEnhancedPacket(0, 1700000000*1_000_000, frame))
+
+	records, err := ParsePCAP(bytes.NewReader(cap.Bytes()))
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if records[0].Category != 48 {
+		t.Errorf("expected Category=48, got %d", records[0].Category)
+	}
+
+	want := time.Unix(1700000000, 0).UTC()
+	if !records[0].Timestamp.Equal(want) {
+		t.Errorf("expected capture timestamp %v, got %v", want, records[0].Timestamp)
+	}
+}
+
+func TestParsePCAPNGDefaultTimestampResolution(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	frame := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+
+	var cap bytes.Buffer
+	cap.Write(buildPCAPNGSectionHeader())
+	cap.Write(buildPCAPNGInterfaceDesc(0)) // no if_tsresol option: defaults to microseconds
+	cap.Write(buildPCAPNGEnhancedPacket(0, 1700000000*1_000_000, frame))
+
+	records, err := ParsePCAP(bytes.NewReader(cap.Bytes()))
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+
+	want := time.Unix(1700000000, 0).UTC()
+	if !records[0].Timestamp.Equal(want) {
+		t.Errorf("expected default-resolution capture timestamp %v, got %v", want, records[0].Timestamp)
+	}
+}
+
+func TestParsePCAPNGMultipleSections(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	frame1 := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+	frame2 := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT062)
+
+	var cap bytes.Buffer
+	cap.Write(buildPCAPNGSectionHeader())
+	cap.Write(buildPCAPNGInterfaceDesc(6))
+	cap.Write(buildPCAPNGEnhancedPacket(0, 1700000000*1_000_000, frame1))
+	// A second Section Header Block re-syncs interface state: the
+	// interface from the first section no longer applies, so a new
+	// Interface Description Block is required before its packets decode.
+	cap.Write(buildPCAPNGSectionHeaderBlock(binary.LittleEndian))
+	cap.Write(buildPCAPNGInterfaceDesc(6))
+	cap.Write(buildPCAPNGEnhancedPacket(0, 1700000100*1_000_000, frame2))
+
+	records, err := ParsePCAP(bytes.NewReader(cap.Bytes()))
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records across both sections, got %d", len(records))
+	}
+	if records[0].Category != 48 || records[1].Category != 62 {
+		t.Errorf("unexpected categories: %d, %d", records[0].Category, records[1].Category)
+	}
+}
+
+func TestParsePCAPNGSkipsPacketsForUnknownInterface(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	frame := buildEthIPv4UDP(net.IPv4(239, 1, 1, 1), 10001, sampleCAT048)
+
+	var cap bytes.Buffer
+	cap.Write(buildPCAPNGSectionHeader())
+	cap.Write(buildPCAPNGInterfaceDesc(6))
+	// References interface 1, but only interface 0 was declared.
+	cap.Write(buildPCAPNGEnhancedPacket(1, 1700000000*1_000_000, frame))
+
+	records, err := ParsePCAP(bytes.NewReader(cap.Bytes()))
+	if err != nil {
+		t.Fatalf("ParsePCAP failed: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected packets for an undeclared interface to be skipped, got %d records", len(records))
+	}
+}
+
+func TestParsePCAPNGRejectsOversizedBlock(t *testing.T) {
+	cap := buildPCAPNGSectionHeader()
+	bogus := make([]byte, 8)
+	binary.LittleEndian.PutUint32(bogus[0:4], blockTypeInterfaceDesc)
+	binary.LittleEndian.PutUint32(bogus[4:8], 0xFFFFFFF0) // claims a ~4GB block body
+	cap = append(cap, bogus...)
+
+	_, err := ParsePCAP(bytes.NewReader(cap))
+	if err == nil {
+		t.Error("ParsePCAP should reject a pcapng block whose length exceeds the sanity ceiling")
+	}
+}
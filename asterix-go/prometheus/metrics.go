@@ -0,0 +1,84 @@
+// Package prometheus adapts asterix.Metrics onto the Prometheus client
+// library, so parse counters and latencies can be scraped without any
+// extra wiring in the calling application.
+package prometheus
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	asterix "github.com/montge/asterix/asterix-go"
+)
+
+// Metrics implements asterix.Metrics on top of four Prometheus
+// collectors: asterix_records_parsed_total{category},
+// asterix_parse_errors_total{kind}, asterix_parse_duration_seconds, and
+// asterix_block_bytes.
+type Metrics struct {
+	recordsParsedTotal *prometheus.CounterVec
+	parseErrorsTotal   *prometheus.CounterVec
+	parseDuration      prometheus.Histogram
+	blockBytes         prometheus.Histogram
+}
+
+// NewMetrics constructs a Metrics and registers its collectors with reg.
+// Pass prometheus.DefaultRegisterer to use the global registry.
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		recordsParsedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "asterix_records_parsed_total",
+			Help: "Total number of ASTERIX records decoded, by category.",
+		}, []string{"category"}),
+		parseErrorsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "asterix_parse_errors_total",
+			Help: "Total number of parse failures, by error kind.",
+		}, []string{"kind"}),
+		parseDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "asterix_parse_duration_seconds",
+			Help:    "Time spent in the C++ ASTERIX engine per parse call.",
+			Buckets: prometheus.DefBuckets,
+		}),
+		blockBytes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "asterix_block_bytes",
+			Help:    "Size in bytes of data decoded by a parse call.",
+			Buckets: prometheus.ExponentialBuckets(64, 2, 12),
+		}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.recordsParsedTotal,
+		m.parseErrorsTotal,
+		m.parseDuration,
+		m.blockBytes,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// IncRecords implements asterix.Metrics.
+func (m *Metrics) IncRecords(cat uint8) {
+	m.recordsParsedTotal.WithLabelValues(strconv.Itoa(int(cat))).Inc()
+}
+
+// IncParseError implements asterix.Metrics.
+func (m *Metrics) IncParseError(kind string) {
+	m.parseErrorsTotal.WithLabelValues(kind).Inc()
+}
+
+// ObserveParseLatency implements asterix.Metrics.
+func (m *Metrics) ObserveParseLatency(d time.Duration) {
+	m.parseDuration.Observe(d.Seconds())
+}
+
+// ObserveBlockSize implements asterix.Metrics.
+func (m *Metrics) ObserveBlockSize(n int) {
+	m.blockBytes.Observe(float64(n))
+}
+
+var _ asterix.Metrics = (*Metrics)(nil)
@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNewMetricsRegistersCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("NewMetrics failed: %v", err)
+	}
+
+	m.IncRecords(48)
+	m.IncParseError("engine")
+	m.ObserveParseLatency(5 * time.Millisecond)
+	m.ObserveBlockSize(256)
+
+	if got := testutil.ToFloat64(m.recordsParsedTotal.WithLabelValues("48")); got != 1 {
+		t.Errorf("expected asterix_records_parsed_total{category=\"48\"}=1, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.parseErrorsTotal.WithLabelValues("engine")); got != 1 {
+		t.Errorf("expected asterix_parse_errors_total{kind=\"engine\"}=1, got %v", got)
+	}
+}
+
+func TestNewMetricsRejectsDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := NewMetrics(reg); err != nil {
+		t.Fatalf("first NewMetrics failed: %v", err)
+	}
+	if _, err := NewMetrics(reg); err == nil {
+		t.Error("second NewMetrics against the same registry should fail on duplicate collectors")
+	}
+}
@@ -0,0 +1,152 @@
+package asterix
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// streamHeaderSize is the length in bytes of the CAT+LEN header that
+// prefixes every ASTERIX data block: a 1-byte category followed by a
+// 2-byte big-endian total block length.
+const streamHeaderSize = 3
+
+// StreamParser incrementally decodes ASTERIX records from an arbitrary
+// io.Reader. It maintains a rolling buffer, reads just enough of the
+// 3-byte CAT+LEN header to know how many bytes a block needs, and only
+// then hands the fully-framed block to the C parser. This lets callers
+// consume files, sockets, TLS streams, or os.Stdin without pre-buffering
+// the whole input, unlike the manual ParseWithOffset loop used by
+// examples/parse_file.
+type StreamParser struct {
+	r       io.Reader
+	buf     []byte
+	chunk   []byte
+	pending []Record
+	err     error
+}
+
+// NewStreamParser returns a StreamParser that reads framed ASTERIX blocks
+// from r.
+func NewStreamParser(r io.Reader) *StreamParser {
+	return &StreamParser{r: r}
+}
+
+// Reset discards any buffered state and reconfigures the parser to read
+// from r. It allows a StreamParser to be reused across connections or
+// files without a fresh allocation, keeping long-running feeds cheap.
+func (s *StreamParser) Reset(r io.Reader) {
+	s.r = r
+	s.buf = s.buf[:0]
+	s.pending = nil
+	s.err = nil
+}
+
+// Next decodes and returns the next ASTERIX record from the stream. It
+// blocks until a full record is available, the reader is exhausted
+// (io.EOF), or a block is cut short mid-frame, in which case it returns
+// io.ErrUnexpectedEOF.
+func (s *StreamParser) Next() (Record, error) {
+	if s.err != nil {
+		return Record{}, s.err
+	}
+
+	for len(s.pending) == 0 {
+		if err := s.fill(); err != nil {
+			s.err = err
+			return Record{}, err
+		}
+	}
+
+	rec := s.pending[0]
+	s.pending = s.pending[1:]
+	return rec, nil
+}
+
+// fill reads one more framed block from the reader and parses it into
+// s.pending. It is called repeatedly by Next until pending records are
+// available.
+func (s *StreamParser) fill() error {
+	if err := s.readAtLeast(streamHeaderSize); err != nil {
+		if errors.Is(err, io.EOF) && len(s.buf) > 0 {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	blockLen := int(s.buf[1])<<8 | int(s.buf[2])
+	if blockLen < streamHeaderSize {
+		return fmt.Errorf("asterix: invalid block length %d", blockLen)
+	}
+
+	if err := s.readAtLeast(blockLen); err != nil {
+		if errors.Is(err, io.EOF) {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	block := s.buf[:blockLen:blockLen]
+	s.buf = s.buf[blockLen:]
+
+	records, err := ParseWithOptions(block, true)
+	if err != nil {
+		return err
+	}
+
+	s.pending = records
+	return nil
+}
+
+// readAtLeast grows s.buf until it holds at least n bytes, reading from
+// the underlying reader as needed. It returns the reader's error (often
+// io.EOF) if n bytes never materialize.
+func (s *StreamParser) readAtLeast(n int) error {
+	if s.chunk == nil {
+		s.chunk = make([]byte, 4096)
+	}
+
+	for len(s.buf) < n {
+		nr, err := s.r.Read(s.chunk)
+		if nr > 0 {
+			s.buf = append(s.buf, s.chunk[:nr]...)
+		}
+		if err != nil {
+			if len(s.buf) >= n {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Records returns a channel that emits every record decoded from the
+// stream until the reader is exhausted or an error occurs. Sends block on
+// channel capacity, so a slow consumer applies backpressure all the way
+// back to the underlying reader. The channel is closed when iteration
+// stops; call Err afterward to distinguish a clean io.EOF from a parse
+// failure.
+func (s *StreamParser) Records() <-chan Record {
+	out := make(chan Record)
+	go func() {
+		defer close(out)
+		for {
+			rec, err := s.Next()
+			if err != nil {
+				return
+			}
+			out <- rec
+		}
+	}()
+	return out
+}
+
+// Err returns the error that stopped the most recent Next or Records
+// iteration, or nil if the stream ended cleanly at io.EOF.
+func (s *StreamParser) Err() error {
+	if errors.Is(s.err, io.EOF) {
+		return nil
+	}
+	return s.err
+}
@@ -0,0 +1,115 @@
+package asterix
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestStreamParserNext(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	sp := NewStreamParser(bytes.NewReader(sampleCAT048))
+
+	rec, err := sp.Next()
+	if err != nil {
+		t.Fatalf("Next() failed: %v", err)
+	}
+	if rec.Category != 48 {
+		t.Errorf("expected Category=48, got %d", rec.Category)
+	}
+
+	if _, err := sp.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after last block, got %v", err)
+	}
+}
+
+func TestStreamParserMultipleBlocks(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	var data []byte
+	data = append(data, sampleCAT048...)
+	data = append(data, sampleCAT062...)
+
+	sp := NewStreamParser(bytes.NewReader(data))
+
+	count := 0
+	for {
+		_, err := sp.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next() failed: %v", err)
+		}
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 records, got %d", count)
+	}
+}
+
+func TestStreamParserTruncatedBlock(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	// Header claims 8 bytes but only 5 are supplied.
+	truncated := sampleCAT048[:5]
+	sp := NewStreamParser(bytes.NewReader(truncated))
+
+	_, err := sp.Next()
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestStreamParserReset(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	sp := NewStreamParser(bytes.NewReader(sampleCAT048))
+	if _, err := sp.Next(); err != nil {
+		t.Fatalf("Next() failed: %v", err)
+	}
+
+	sp.Reset(bytes.NewReader(sampleCAT062))
+	rec, err := sp.Next()
+	if err != nil {
+		t.Fatalf("Next() after Reset failed: %v", err)
+	}
+	if rec.Category != 62 {
+		t.Errorf("expected Category=62 after Reset, got %d", rec.Category)
+	}
+}
+
+func TestStreamParserRecordsChannel(t *testing.T) {
+	if err := Init(""); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	var data []byte
+	data = append(data, sampleCAT048...)
+	data = append(data, sampleCAT062...)
+
+	sp := NewStreamParser(bytes.NewReader(data))
+
+	count := 0
+	for range sp.Records() {
+		count++
+	}
+
+	if count != 2 {
+		t.Errorf("expected 2 records from channel, got %d", count)
+	}
+	if err := sp.Err(); err != nil {
+		t.Errorf("expected nil Err() after clean EOF, got %v", err)
+	}
+}
@@ -0,0 +1,509 @@
+package asterix
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrCategoryMismatch is returned by Record.As when the record's Category
+// does not match the destination message type.
+var ErrCategoryMismatch = fmt.Errorf("asterix: record category does not match destination type")
+
+// DataSource identifies the sensor that produced a record: System Area
+// Code and System Identification Code (item I010 in every category below).
+type DataSource struct {
+	SAC uint8 `json:"SAC"`
+	SIC uint8 `json:"SIC"`
+}
+
+// Velocity is a Cartesian ground velocity expressed in engineering units.
+type Velocity struct {
+	Vx float64 `json:"Vx"` // m/s
+	Vy float64 `json:"Vy"` // m/s
+}
+
+// CAT001Message is the typed form of a Category 001 (Monoradar Target
+// Reports, legacy) record.
+type CAT001Message struct {
+	DataSource  DataSource
+	Time        float64 // truncated time of day (I141), seconds
+	Rho         float64 // NM
+	Theta       float64 // degrees
+	TrackNumber uint16
+	Mode3A      uint16
+	FlightLevel float64 // FL (100s of feet)
+}
+
+// CAT002Message is the typed form of a Category 002 (Transmission of
+// Monoradar Service Messages) record.
+type CAT002Message struct {
+	DataSource   DataSource
+	MessageType  uint8
+	SectorNumber uint8
+	TimeOfDay    float64 // seconds since midnight
+}
+
+// CAT021Message is the typed form of a Category 021 (ADS-B Target
+// Reports) record.
+type CAT021Message struct {
+	DataSource           DataSource
+	TargetAddress        uint32 // ICAO 24-bit aircraft address
+	TargetIdentification string // callsign
+	EmitterCategory      uint8
+	Latitude             float64 // degrees WGS-84
+	Longitude            float64 // degrees WGS-84
+	GeometricAltitude    float64 // feet
+	FlightLevel          float64 // FL (100s of feet)
+	GroundSpeed          float64 // knots
+	TrackAngle           float64 // degrees
+}
+
+// CAT034Message is the typed form of a Category 034 (Transmission of
+// Monoradar Service Messages, the CAT002 successor) record.
+type CAT034Message struct {
+	DataSource            DataSource
+	MessageType           uint8
+	TimeOfDay             float64 // seconds since midnight
+	AntennaRotationPeriod float64 // seconds
+}
+
+// CAT048Message is the typed form of a Category 048 (Monoradar Target
+// Reports) record.
+type CAT048Message struct {
+	DataSource    DataSource
+	TimeOfDay     float64 // seconds since midnight
+	Rho           float64 // NM
+	Theta         float64 // degrees
+	Mode3A        uint16
+	FlightLevel   float64 // FL (100s of feet)
+	TrackNumber   uint16
+	TargetAddress uint32 // ICAO 24-bit aircraft address, if reported
+	GroundSpeed   float64 // NM/s
+	Heading       float64 // degrees
+}
+
+// CAT062Message is the typed form of a Category 062 (System Track Data)
+// record.
+type CAT062Message struct {
+	DataSource    DataSource
+	TrackNumber   uint16
+	TimeOfTrack   float64 // seconds since midnight
+	Latitude      float64 // degrees WGS-84
+	Longitude     float64 // degrees WGS-84
+	CartesianX    float64 // NM
+	CartesianY    float64 // NM
+	TrackVelocity Velocity
+	Mode3A        uint16
+	FlightLevel   float64 // FL (100s of feet)
+	TargetAddress uint32  // ICAO 24-bit aircraft address, if reported
+}
+
+// CAT065Message is the typed form of a Category 065 (SDPS Service Status
+// Messages) record.
+type CAT065Message struct {
+	DataSource                 DataSource
+	MessageType                uint8
+	ServiceIdentification      uint8
+	SDPSConfigurationAndStatus uint8
+	TimeOfMessage              float64 // seconds since midnight
+}
+
+// The wire* types below mirror the shape of the JSON the C engine
+// produces closely enough that encoding/json can decode straight into
+// them with no map[string]interface{} or interface{} type assertions in
+// between. Record.As unmarshals r.JSON directly into one of these
+// instead of going through Items, which builds and caches a generic
+// map — the thing the typed-decoder request calls out as slow. Items of
+// the same shape in different categories share a wire type:
+// wirePolarPosition serves I040 of CAT001 and CAT048, and wireLatLon
+// serves CAT021 I130 and CAT062 I105.
+type wirePolarPosition struct {
+	RHO   float64 `json:"RHO"`
+	THETA float64 `json:"THETA"`
+}
+
+type wireLatLon struct {
+	Latitude  float64 `json:"Latitude"`
+	Longitude float64 `json:"Longitude"`
+}
+
+type wireCartesian struct {
+	X float64 `json:"X"`
+	Y float64 `json:"Y"`
+}
+
+type wireGroundVector struct {
+	GroundSpeed float64 `json:"GroundSpeed"`
+	TrackAngle  float64 `json:"TrackAngle"`
+}
+
+type wireGroundVectorHeading struct {
+	GroundSpeed float64 `json:"GroundSpeed"`
+	Heading     float64 `json:"Heading"`
+}
+
+type cat001Wire struct {
+	I010 *DataSource        `json:"I010"`
+	I141 *struct {
+		TruncatedTimeOfDay float64 `json:"TruncatedTimeOfDay"`
+	} `json:"I141"`
+	I040 *wirePolarPosition `json:"I040"`
+	I161 *struct {
+		TrackNumber uint16 `json:"TrackNumber"`
+	} `json:"I161"`
+	I070 *struct {
+		Mode3A uint16 `json:"Mode3A"`
+	} `json:"I070"`
+	I090 *struct {
+		FlightLevel float64 `json:"FlightLevel"`
+	} `json:"I090"`
+}
+
+type cat002Wire struct {
+	I010 *DataSource `json:"I010"`
+	I000 *struct {
+		MessageType uint8 `json:"MessageType"`
+	} `json:"I000"`
+	I020 *struct {
+		SectorNumber uint8 `json:"SectorNumber"`
+	} `json:"I020"`
+	I030 *struct {
+		TimeOfDay float64 `json:"TimeOfDay"`
+	} `json:"I030"`
+}
+
+type cat021Wire struct {
+	I010 *DataSource `json:"I010"`
+	I080 *struct {
+		TargetAddress uint32 `json:"TargetAddress"`
+	} `json:"I080"`
+	I170 *struct {
+		TargetIdentification string `json:"TargetIdentification"`
+	} `json:"I170"`
+	I020 *struct {
+		EmitterCategory uint8 `json:"EmitterCategory"`
+	} `json:"I020"`
+	I130 *wireLatLon `json:"I130"`
+	I140 *struct {
+		GeometricHeight float64 `json:"GeometricHeight"`
+	} `json:"I140"`
+	I145 *struct {
+		FlightLevel float64 `json:"FlightLevel"`
+	} `json:"I145"`
+	I160 *wireGroundVector `json:"I160"`
+}
+
+type cat034Wire struct {
+	I010 *DataSource `json:"I010"`
+	I000 *struct {
+		MessageType uint8 `json:"MessageType"`
+	} `json:"I000"`
+	I030 *struct {
+		TimeOfDay float64 `json:"TimeOfDay"`
+	} `json:"I030"`
+	I041 *struct {
+		AntennaRotationPeriod float64 `json:"AntennaRotationPeriod"`
+	} `json:"I041"`
+}
+
+type cat048Wire struct {
+	I010 *DataSource `json:"I010"`
+	I140 *struct {
+		TimeOfDay float64 `json:"TimeOfDay"`
+	} `json:"I140"`
+	I040 *wirePolarPosition `json:"I040"`
+	I070 *struct {
+		Mode3A uint16 `json:"Mode3A"`
+	} `json:"I070"`
+	I090 *struct {
+		FlightLevel float64 `json:"FlightLevel"`
+	} `json:"I090"`
+	I161 *struct {
+		TrackNumber uint16 `json:"TrackNumber"`
+	} `json:"I161"`
+	I220 *struct {
+		TargetAddress uint32 `json:"TargetAddress"`
+	} `json:"I220"`
+	I200 *wireGroundVectorHeading `json:"I200"`
+}
+
+type cat062Wire struct {
+	I010 *DataSource `json:"I010"`
+	I040 *struct {
+		TrackNumber uint16 `json:"TrackNumber"`
+	} `json:"I040"`
+	I070 *struct {
+		TimeOfTrack float64 `json:"TimeOfTrack"`
+	} `json:"I070"`
+	I105 *wireLatLon    `json:"I105"`
+	I100 *wireCartesian `json:"I100"`
+	I185 *Velocity      `json:"I185"`
+	I060 *struct {
+		Mode3ACode uint16 `json:"Mode3ACode"`
+	} `json:"I060"`
+	I136 *struct {
+		MeasuredFlightLevel float64 `json:"MeasuredFlightLevel"`
+	} `json:"I136"`
+	I080 *struct {
+		TargetAddress uint32 `json:"TargetAddress"`
+	} `json:"I080"`
+}
+
+type cat065Wire struct {
+	I010 *DataSource `json:"I010"`
+	I000 *struct {
+		MessageType uint8 `json:"MessageType"`
+	} `json:"I000"`
+	I015 *struct {
+		ServiceIdentification uint8 `json:"ServiceIdentification"`
+	} `json:"I015"`
+	I020 *struct {
+		SDPSConfigurationAndStatus uint8 `json:"SDPSConfigurationAndStatus"`
+	} `json:"I020"`
+	I030 *struct {
+		TimeOfMessage float64 `json:"TimeOfMessage"`
+	} `json:"I030"`
+}
+
+// As decodes r into dst, which must be a pointer to one of the typed
+// message structs in this file (e.g. *CAT048Message). It returns
+// ErrCategoryMismatch if r.Category does not match dst's category.
+//
+// As unmarshals r.JSON directly into a struct shaped like the relevant
+// items, so it never builds the map[string]interface{} that Items does
+// — no interface boxing and no type assertions per field, just one
+// decode pass into concrete types.
+//
+// Partial delivery: the original request asked for struct population
+// straight from the C record buffer, bypassing the JSON string
+// entirely, and for the wire types below to be generated from the XML
+// category definitions the C++ engine uses. Neither landed here — As
+// still round-trips through r.JSON (just into typed structs instead of
+// a map), and cat001Wire..cat065Wire are hand-written, not generated.
+// Both remain open: this binding does not yet expose the C buffer's
+// layout to Go, and there is no XML codegen step in this repo yet. A
+// future revision should add both rather than grow more hand-written
+// wire types from memory the way this one did.
+func (r *Record) As(dst interface{}) error {
+	switch v := dst.(type) {
+	case *CAT001Message:
+		if r.Category != 1 {
+			return ErrCategoryMismatch
+		}
+		var w cat001Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT001 record: %w", err)
+		}
+		populateCAT001(v, &w)
+	case *CAT002Message:
+		if r.Category != 2 {
+			return ErrCategoryMismatch
+		}
+		var w cat002Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT002 record: %w", err)
+		}
+		populateCAT002(v, &w)
+	case *CAT021Message:
+		if r.Category != 21 {
+			return ErrCategoryMismatch
+		}
+		var w cat021Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT021 record: %w", err)
+		}
+		populateCAT021(v, &w)
+	case *CAT034Message:
+		if r.Category != 34 {
+			return ErrCategoryMismatch
+		}
+		var w cat034Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT034 record: %w", err)
+		}
+		populateCAT034(v, &w)
+	case *CAT048Message:
+		if r.Category != 48 {
+			return ErrCategoryMismatch
+		}
+		var w cat048Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT048 record: %w", err)
+		}
+		populateCAT048(v, &w)
+	case *CAT062Message:
+		if r.Category != 62 {
+			return ErrCategoryMismatch
+		}
+		var w cat062Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT062 record: %w", err)
+		}
+		populateCAT062(v, &w)
+	case *CAT065Message:
+		if r.Category != 65 {
+			return ErrCategoryMismatch
+		}
+		var w cat065Wire
+		if err := json.Unmarshal([]byte(r.JSON), &w); err != nil {
+			return fmt.Errorf("asterix: decoding CAT065 record: %w", err)
+		}
+		populateCAT065(v, &w)
+	default:
+		return fmt.Errorf("asterix: unsupported destination type %T", dst)
+	}
+	return nil
+}
+
+func populateDataSource(ds *DataSource) DataSource {
+	if ds == nil {
+		return DataSource{}
+	}
+	return *ds
+}
+
+func populateCAT001(m *CAT001Message, w *cat001Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I141 != nil {
+		m.Time = w.I141.TruncatedTimeOfDay
+	}
+	if w.I040 != nil {
+		m.Rho = w.I040.RHO
+		m.Theta = w.I040.THETA
+	}
+	if w.I161 != nil {
+		m.TrackNumber = w.I161.TrackNumber
+	}
+	if w.I070 != nil {
+		m.Mode3A = w.I070.Mode3A
+	}
+	if w.I090 != nil {
+		m.FlightLevel = w.I090.FlightLevel
+	}
+}
+
+func populateCAT002(m *CAT002Message, w *cat002Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I000 != nil {
+		m.MessageType = w.I000.MessageType
+	}
+	if w.I020 != nil {
+		m.SectorNumber = w.I020.SectorNumber
+	}
+	if w.I030 != nil {
+		m.TimeOfDay = w.I030.TimeOfDay
+	}
+}
+
+func populateCAT021(m *CAT021Message, w *cat021Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I080 != nil {
+		m.TargetAddress = w.I080.TargetAddress
+	}
+	if w.I170 != nil {
+		m.TargetIdentification = w.I170.TargetIdentification
+	}
+	if w.I020 != nil {
+		m.EmitterCategory = w.I020.EmitterCategory
+	}
+	if w.I130 != nil {
+		m.Latitude = w.I130.Latitude
+		m.Longitude = w.I130.Longitude
+	}
+	if w.I140 != nil {
+		m.GeometricAltitude = w.I140.GeometricHeight
+	}
+	if w.I145 != nil {
+		m.FlightLevel = w.I145.FlightLevel
+	}
+	if w.I160 != nil {
+		m.GroundSpeed = w.I160.GroundSpeed
+		m.TrackAngle = w.I160.TrackAngle
+	}
+}
+
+func populateCAT034(m *CAT034Message, w *cat034Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I000 != nil {
+		m.MessageType = w.I000.MessageType
+	}
+	if w.I030 != nil {
+		m.TimeOfDay = w.I030.TimeOfDay
+	}
+	if w.I041 != nil {
+		m.AntennaRotationPeriod = w.I041.AntennaRotationPeriod
+	}
+}
+
+func populateCAT048(m *CAT048Message, w *cat048Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I140 != nil {
+		m.TimeOfDay = w.I140.TimeOfDay
+	}
+	if w.I040 != nil {
+		m.Rho = w.I040.RHO
+		m.Theta = w.I040.THETA
+	}
+	if w.I070 != nil {
+		m.Mode3A = w.I070.Mode3A
+	}
+	if w.I090 != nil {
+		m.FlightLevel = w.I090.FlightLevel
+	}
+	if w.I161 != nil {
+		m.TrackNumber = w.I161.TrackNumber
+	}
+	if w.I220 != nil {
+		m.TargetAddress = w.I220.TargetAddress
+	}
+	if w.I200 != nil {
+		m.GroundSpeed = w.I200.GroundSpeed
+		m.Heading = w.I200.Heading
+	}
+}
+
+func populateCAT062(m *CAT062Message, w *cat062Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I040 != nil {
+		m.TrackNumber = w.I040.TrackNumber
+	}
+	if w.I070 != nil {
+		m.TimeOfTrack = w.I070.TimeOfTrack
+	}
+	if w.I105 != nil {
+		m.Latitude = w.I105.Latitude
+		m.Longitude = w.I105.Longitude
+	}
+	if w.I100 != nil {
+		m.CartesianX = w.I100.X
+		m.CartesianY = w.I100.Y
+	}
+	if w.I185 != nil {
+		m.TrackVelocity = *w.I185
+	}
+	if w.I060 != nil {
+		m.Mode3A = w.I060.Mode3ACode
+	}
+	if w.I136 != nil {
+		m.FlightLevel = w.I136.MeasuredFlightLevel
+	}
+	if w.I080 != nil {
+		m.TargetAddress = w.I080.TargetAddress
+	}
+}
+
+func populateCAT065(m *CAT065Message, w *cat065Wire) {
+	m.DataSource = populateDataSource(w.I010)
+	if w.I000 != nil {
+		m.MessageType = w.I000.MessageType
+	}
+	if w.I015 != nil {
+		m.ServiceIdentification = w.I015.ServiceIdentification
+	}
+	if w.I020 != nil {
+		m.SDPSConfigurationAndStatus = w.I020.SDPSConfigurationAndStatus
+	}
+	if w.I030 != nil {
+		m.TimeOfMessage = w.I030.TimeOfMessage
+	}
+}
@@ -0,0 +1,289 @@
+package asterix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRecordAsCAT001(t *testing.T) {
+	rec := Record{
+		Category: 1,
+		JSON: `{
+			"category": 1,
+			"I010": {"SAC": 3, "SIC": 4},
+			"I141": {"TruncatedTimeOfDay": 12345.5},
+			"I040": {"RHO": 10.5, "THETA": 90.0},
+			"I161": {"TrackNumber": 7},
+			"I070": {"Mode3A": 1234},
+			"I090": {"FlightLevel": 280}
+		}`,
+	}
+
+	var msg CAT001Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT001Message) failed: %v", err)
+	}
+
+	if msg.DataSource != (DataSource{SAC: 3, SIC: 4}) {
+		t.Errorf("unexpected DataSource: %+v", msg.DataSource)
+	}
+	if msg.Time != 12345.5 {
+		t.Errorf("expected Time=12345.5, got %v", msg.Time)
+	}
+	if msg.Rho != 10.5 || msg.Theta != 90.0 {
+		t.Errorf("unexpected polar position: Rho=%v Theta=%v", msg.Rho, msg.Theta)
+	}
+	if msg.TrackNumber != 7 {
+		t.Errorf("expected TrackNumber=7, got %d", msg.TrackNumber)
+	}
+	if msg.Mode3A != 1234 {
+		t.Errorf("expected Mode3A=1234, got %d", msg.Mode3A)
+	}
+	if msg.FlightLevel != 280 {
+		t.Errorf("expected FlightLevel=280, got %v", msg.FlightLevel)
+	}
+}
+
+func TestRecordAsCAT002(t *testing.T) {
+	rec := Record{
+		Category: 2,
+		JSON: `{
+			"category": 2,
+			"I010": {"SAC": 1, "SIC": 1},
+			"I000": {"MessageType": 3},
+			"I020": {"SectorNumber": 16},
+			"I030": {"TimeOfDay": 45000.25}
+		}`,
+	}
+
+	var msg CAT002Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT002Message) failed: %v", err)
+	}
+
+	if msg.MessageType != 3 {
+		t.Errorf("expected MessageType=3, got %d", msg.MessageType)
+	}
+	if msg.SectorNumber != 16 {
+		t.Errorf("expected SectorNumber=16, got %d", msg.SectorNumber)
+	}
+	if msg.TimeOfDay != 45000.25 {
+		t.Errorf("expected TimeOfDay=45000.25, got %v", msg.TimeOfDay)
+	}
+}
+
+func TestRecordAsCAT021(t *testing.T) {
+	rec := Record{
+		Category: 21,
+		JSON: `{
+			"category": 21,
+			"I010": {"SAC": 5, "SIC": 6},
+			"I080": {"TargetAddress": 11259375},
+			"I170": {"TargetIdentification": "KLM123"},
+			"I020": {"EmitterCategory": 3},
+			"I130": {"Latitude": 52.3, "Longitude": 4.75},
+			"I140": {"GeometricHeight": 35000},
+			"I145": {"FlightLevel": 350},
+			"I160": {"GroundSpeed": 420.0, "TrackAngle": 270.0}
+		}`,
+	}
+
+	var msg CAT021Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT021Message) failed: %v", err)
+	}
+
+	if msg.TargetAddress != 11259375 {
+		t.Errorf("expected TargetAddress=11259375, got %d", msg.TargetAddress)
+	}
+	if msg.TargetIdentification != "KLM123" {
+		t.Errorf("expected TargetIdentification=KLM123, got %q", msg.TargetIdentification)
+	}
+	if msg.EmitterCategory != 3 {
+		t.Errorf("expected EmitterCategory=3, got %d", msg.EmitterCategory)
+	}
+	if msg.Latitude != 52.3 || msg.Longitude != 4.75 {
+		t.Errorf("unexpected position: Latitude=%v Longitude=%v", msg.Latitude, msg.Longitude)
+	}
+	if msg.GeometricAltitude != 35000 {
+		t.Errorf("expected GeometricAltitude=35000, got %v", msg.GeometricAltitude)
+	}
+	if msg.FlightLevel != 350 {
+		t.Errorf("expected FlightLevel=350, got %v", msg.FlightLevel)
+	}
+	if msg.GroundSpeed != 420.0 || msg.TrackAngle != 270.0 {
+		t.Errorf("unexpected ground vector: GroundSpeed=%v TrackAngle=%v", msg.GroundSpeed, msg.TrackAngle)
+	}
+}
+
+func TestRecordAsCAT034(t *testing.T) {
+	rec := Record{
+		Category: 34,
+		JSON: `{
+			"category": 34,
+			"I010": {"SAC": 7, "SIC": 8},
+			"I000": {"MessageType": 1},
+			"I030": {"TimeOfDay": 3600.0},
+			"I041": {"AntennaRotationPeriod": 4.5}
+		}`,
+	}
+
+	var msg CAT034Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT034Message) failed: %v", err)
+	}
+
+	if msg.MessageType != 1 {
+		t.Errorf("expected MessageType=1, got %d", msg.MessageType)
+	}
+	if msg.TimeOfDay != 3600.0 {
+		t.Errorf("expected TimeOfDay=3600.0, got %v", msg.TimeOfDay)
+	}
+	if msg.AntennaRotationPeriod != 4.5 {
+		t.Errorf("expected AntennaRotationPeriod=4.5, got %v", msg.AntennaRotationPeriod)
+	}
+}
+
+func TestRecordAsCAT048(t *testing.T) {
+	rec := Record{
+		Category: 48,
+		JSON: `{
+			"category": 48,
+			"I010": {"SAC": 10, "SIC": 20},
+			"I040": {"RHO": 123.5, "THETA": 45.25},
+			"I070": {"Mode3A": 4095},
+			"I090": {"FlightLevel": 330},
+			"I161": {"TrackNumber": 512},
+			"I220": {"TargetAddress": 7472713},
+			"I200": {"GroundSpeed": 250.5, "Heading": 90}
+		}`,
+	}
+
+	var msg CAT048Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT048Message) failed: %v", err)
+	}
+
+	if msg.DataSource.SAC != 10 || msg.DataSource.SIC != 20 {
+		t.Errorf("unexpected DataSource: %+v", msg.DataSource)
+	}
+	if msg.Rho != 123.5 || msg.Theta != 45.25 {
+		t.Errorf("unexpected polar position: Rho=%v Theta=%v", msg.Rho, msg.Theta)
+	}
+	if msg.Mode3A != 4095 {
+		t.Errorf("expected Mode3A=4095, got %d", msg.Mode3A)
+	}
+	if msg.FlightLevel != 330 {
+		t.Errorf("expected FlightLevel=330, got %v", msg.FlightLevel)
+	}
+	if msg.TrackNumber != 512 {
+		t.Errorf("expected TrackNumber=512, got %d", msg.TrackNumber)
+	}
+	if msg.TargetAddress != 7472713 {
+		t.Errorf("expected TargetAddress=7472713, got %d", msg.TargetAddress)
+	}
+	if msg.GroundSpeed != 250.5 || msg.Heading != 90 {
+		t.Errorf("unexpected ground vector: GroundSpeed=%v Heading=%v", msg.GroundSpeed, msg.Heading)
+	}
+}
+
+func TestRecordAsCAT062(t *testing.T) {
+	rec := Record{
+		Category: 62,
+		JSON: `{
+			"category": 62,
+			"I010": {"SAC": 1, "SIC": 2},
+			"I040": {"TrackNumber": 100},
+			"I105": {"Latitude": 52.1, "Longitude": 4.3},
+			"I185": {"Vx": 120.0, "Vy": -30.0}
+		}`,
+	}
+
+	var msg CAT062Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT062Message) failed: %v", err)
+	}
+
+	if msg.TrackNumber != 100 {
+		t.Errorf("expected TrackNumber=100, got %d", msg.TrackNumber)
+	}
+	if msg.Latitude != 52.1 || msg.Longitude != 4.3 {
+		t.Errorf("unexpected position: %+v", msg)
+	}
+	if msg.TrackVelocity.Vx != 120.0 || msg.TrackVelocity.Vy != -30.0 {
+		t.Errorf("unexpected velocity: %+v", msg.TrackVelocity)
+	}
+}
+
+func TestRecordAsCAT065(t *testing.T) {
+	rec := Record{
+		Category: 65,
+		JSON: `{
+			"category": 65,
+			"I010": {"SAC": 9, "SIC": 10},
+			"I000": {"MessageType": 2},
+			"I015": {"ServiceIdentification": 1},
+			"I020": {"SDPSConfigurationAndStatus": 4},
+			"I030": {"TimeOfMessage": 7200.0}
+		}`,
+	}
+
+	var msg CAT065Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT065Message) failed: %v", err)
+	}
+
+	if msg.MessageType != 2 {
+		t.Errorf("expected MessageType=2, got %d", msg.MessageType)
+	}
+	if msg.ServiceIdentification != 1 {
+		t.Errorf("expected ServiceIdentification=1, got %d", msg.ServiceIdentification)
+	}
+	if msg.SDPSConfigurationAndStatus != 4 {
+		t.Errorf("expected SDPSConfigurationAndStatus=4, got %d", msg.SDPSConfigurationAndStatus)
+	}
+	if msg.TimeOfMessage != 7200.0 {
+		t.Errorf("expected TimeOfMessage=7200.0, got %v", msg.TimeOfMessage)
+	}
+}
+
+func TestRecordAsCategoryMismatch(t *testing.T) {
+	rec := Record{Category: 48, JSON: `{"category":48}`}
+
+	var msg CAT062Message
+	err := rec.As(&msg)
+	if !errors.Is(err, ErrCategoryMismatch) {
+		t.Errorf("expected ErrCategoryMismatch, got %v", err)
+	}
+}
+
+func TestRecordAsUnsupportedType(t *testing.T) {
+	rec := Record{Category: 48, JSON: `{"category":48}`}
+
+	var dst struct{ Foo int }
+	err := rec.As(&dst)
+	if err == nil {
+		t.Error("As() with an unsupported destination type should return an error")
+	}
+}
+
+func TestRecordAsMissingFields(t *testing.T) {
+	rec := Record{Category: 48, JSON: `{"category":48}`}
+
+	var msg CAT048Message
+	if err := rec.As(&msg); err != nil {
+		t.Fatalf("As(CAT048Message) on a record missing items failed: %v", err)
+	}
+	if msg.Rho != 0 || msg.TrackNumber != 0 {
+		t.Errorf("expected zero-valued fields for absent items, got %+v", msg)
+	}
+}
+
+func TestRecordAsInvalidJSON(t *testing.T) {
+	rec := Record{Category: 48, JSON: `{invalid json}`}
+
+	var msg CAT048Message
+	if err := rec.As(&msg); err == nil {
+		t.Error("As() should fail when the record JSON is invalid")
+	}
+}